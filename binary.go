@@ -0,0 +1,437 @@
+package set
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ErrUnsupportedType is returned when a value cannot be encoded because its
+// type is not supported by the binary format.
+var ErrUnsupportedType = errors.New("set: unsupported value type")
+
+// binaryMagic is the header of the encoded sets. The last byte is the version
+// of the format.
+var binaryMagic = [4]byte{'S', 'E', 'T', binaryVersion}
+
+// binaryVersion is the current version of the binary format.
+const binaryVersion = 1
+
+// Type tags of the encoded values. They are part of the format, so they must
+// not be changed.
+const (
+	tagEnd     byte = 0 // End of the set.
+	tagNil     byte = 1
+	tagFalse   byte = 2
+	tagTrue    byte = 3
+	tagInt     byte = 4 // Zigzag varint.
+	tagInt8    byte = 5
+	tagInt16   byte = 6
+	tagInt32   byte = 7
+	tagInt64   byte = 8
+	tagUint    byte = 9 // Varint.
+	tagUint8   byte = 10
+	tagUint16  byte = 11
+	tagUint32  byte = 12
+	tagUint64  byte = 13
+	tagFloat32 byte = 14 // IEEE 754 bits in little endian.
+	tagFloat64 byte = 15
+	tagString  byte = 16 // Varint length and the bytes.
+)
+
+// Encoder writes the sets into an io.Writer in a compact binary format. Every
+// set starts with a versioned header, and every value is written with a type
+// tag, so the decoded values have the same types. The strings are prefixed
+// with their lengths. The supported types are nil, bool, string, float32,
+// float64 and the integer types including byte; the other types, including the
+// named types, cannot be encoded.
+type Encoder struct {
+	w   *bufio.Writer
+	buf []byte
+	// err is the error which stopped writing in the middle of a set. The
+	// stream is corrupted after it, so it is returned by all next calls.
+	err error
+}
+
+// NewEncoder creates a new *Encoder which writes into w.
+//
+//	enc := set.NewEncoder(file)
+//	err := enc.Encode(s)
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Encode writes the values of s. The values are written while iterating over
+// the set, so the encoded set is never held in the memory, but the thread-safe
+// sets are locked for reading until all values are written. It returns an
+// error which wraps ErrUnsupportedType if a value cannot be encoded, and
+// nothing is written in that case, so the next sets can still be encoded. If
+// the set is changed concurrently and a value cannot be encoded after the set
+// is partially written, or writing fails, the Encoder cannot be used anymore
+// and all next calls return the same error.
+func (e *Encoder) Encode(s Set) error {
+	if e.err != nil {
+		return e.err
+	}
+	var err error
+	s.Each(func(val interface{}) bool {
+		e.buf, err = appendValue(e.buf[:0], val)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(binaryMagic[:]); err != nil {
+		e.err = err
+		return err
+	}
+	s.Each(func(val interface{}) bool {
+		e.buf, err = appendValue(e.buf[:0], val)
+		if err == nil {
+			_, err = e.w.Write(e.buf)
+		}
+		return err == nil
+	})
+	if err == nil {
+		err = e.w.WriteByte(tagEnd)
+	}
+	if err == nil {
+		err = e.w.Flush()
+	}
+	e.err = err
+	return err
+}
+
+// appendValue appends the type tag and the encoding of val into b.
+func appendValue(b []byte, val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(b, tagNil), nil
+	case bool:
+		if v {
+			return append(b, tagTrue), nil
+		}
+		return append(b, tagFalse), nil
+	case int:
+		return binary.AppendVarint(append(b, tagInt), int64(v)), nil
+	case int8:
+		return binary.AppendVarint(append(b, tagInt8), int64(v)), nil
+	case int16:
+		return binary.AppendVarint(append(b, tagInt16), int64(v)), nil
+	case int32:
+		return binary.AppendVarint(append(b, tagInt32), int64(v)), nil
+	case int64:
+		return binary.AppendVarint(append(b, tagInt64), v), nil
+	case uint:
+		return binary.AppendUvarint(append(b, tagUint), uint64(v)), nil
+	case uint8:
+		return binary.AppendUvarint(append(b, tagUint8), uint64(v)), nil
+	case uint16:
+		return binary.AppendUvarint(append(b, tagUint16), uint64(v)), nil
+	case uint32:
+		return binary.AppendUvarint(append(b, tagUint32), uint64(v)), nil
+	case uint64:
+		return binary.AppendUvarint(append(b, tagUint64), v), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(b, tagFloat32), math.Float32bits(v)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(b, tagFloat64), math.Float64bits(v)), nil
+	case string:
+		b = binary.AppendUvarint(append(b, tagString), uint64(len(v)))
+		return append(b, v...), nil
+	}
+	return b, fmt.Errorf("%w: %T", ErrUnsupportedType, val)
+}
+
+// Decoder reads the sets which are written by an Encoder from an io.Reader. It
+// buffers the input, so it may read more data than the sets it decodes.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+// NewDecoder creates a new *Decoder which reads from r.
+//
+//	dec := set.NewDecoder(file)
+//	s := set.New(set.ThreadSafe)
+//	err := dec.Decode(s)
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next set and adds its values into s. The values are added
+// while reading, so the decoded set is never held in the memory, and s may
+// contain some of the values if an error occurs. It returns io.EOF if there
+// is no more set, and an error which wraps ErrInvalidFormat if the data is not
+// valid.
+func (d *Decoder) Decode(s Set) error {
+	return d.decode(s.Add)
+}
+
+// decode reads the next set and calls fn for every value.
+func (d *Decoder) decode(fn func(val interface{})) error {
+	var header [4]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("%w: header is too short", ErrInvalidFormat)
+		}
+		return err
+	}
+	if [3]byte(header[:3]) != [3]byte(binaryMagic[:3]) {
+		return fmt.Errorf("%w: unknown header %q", ErrInvalidFormat, header[:])
+	}
+	if header[3] != binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, header[3])
+	}
+
+	for {
+		tag, err := d.r.ReadByte()
+		if err != nil {
+			return d.unexpected(err)
+		}
+		if tag == tagEnd {
+			return nil
+		}
+		val, err := d.readValue(tag)
+		if err != nil {
+			return err
+		}
+		fn(val)
+	}
+}
+
+// readValue reads the value of the given type tag.
+func (d *Decoder) readValue(tag byte) (interface{}, error) {
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt, tagInt8, tagInt16, tagInt32, tagInt64:
+		ux, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		v := int64(ux >> 1)
+		if ux&1 != 0 {
+			v = ^v
+		}
+		return convertInt(tag, v)
+	case tagUint, tagUint8, tagUint16, tagUint32, tagUint64:
+		v, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		return convertUint(tag, v)
+	case tagFloat32:
+		var b [4]byte
+		if _, err := io.ReadFull(d.r, b[:]); err != nil {
+			return nil, d.unexpected(err)
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b[:])), nil
+	case tagFloat64:
+		var b [8]byte
+		if _, err := io.ReadFull(d.r, b[:]); err != nil {
+			return nil, d.unexpected(err)
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case tagString:
+		n, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: string length %d", ErrInvalidFormat, n)
+		}
+		// The length is not trusted for the allocation, so the corrupted data
+		// cannot allocate more memory than its size.
+		var buf strings.Builder
+		if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+			return nil, d.unexpected(err)
+		}
+		return buf.String(), nil
+	}
+	return nil, fmt.Errorf("%w: unknown type tag %d", ErrInvalidFormat, tag)
+}
+
+// unexpected converts the end of the data in the middle of a set into an
+// error which wraps ErrInvalidFormat.
+func (d *Decoder) unexpected(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+	}
+	return err
+}
+
+// readUvarint reads a varint like binary.ReadUvarint, but it returns an error
+// which wraps ErrInvalidFormat for the corrupted data.
+func (d *Decoder) readUvarint() (uint64, error) {
+	var x uint64
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, d.unexpected(err)
+		}
+		if b < 0x80 {
+			if i == binary.MaxVarintLen64-1 && b > 1 {
+				break
+			}
+			return x | uint64(b)<<(7*i), nil
+		}
+		x |= uint64(b&0x7f) << (7 * i)
+	}
+	return 0, fmt.Errorf("%w: varint overflows 64 bits", ErrInvalidFormat)
+}
+
+// convertInt converts v into the signed integer type of the tag.
+func convertInt(tag byte, v int64) (interface{}, error) {
+	var val interface{}
+	var ok bool
+	switch tag {
+	case tagInt:
+		val, ok = int(v), int64(int(v)) == v
+	case tagInt8:
+		val, ok = int8(v), int64(int8(v)) == v
+	case tagInt16:
+		val, ok = int16(v), int64(int16(v)) == v
+	case tagInt32:
+		val, ok = int32(v), int64(int32(v)) == v
+	default:
+		val, ok = v, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: integer %d out of range", ErrInvalidFormat, v)
+	}
+	return val, nil
+}
+
+// convertUint converts v into the unsigned integer type of the tag.
+func convertUint(tag byte, v uint64) (interface{}, error) {
+	var val interface{}
+	var ok bool
+	switch tag {
+	case tagUint:
+		val, ok = uint(v), uint64(uint(v)) == v
+	case tagUint8:
+		val, ok = uint8(v), uint64(uint8(v)) == v
+	case tagUint16:
+		val, ok = uint16(v), uint64(uint16(v)) == v
+	case tagUint32:
+		val, ok = uint32(v), uint64(uint32(v)) == v
+	default:
+		val, ok = v, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: integer %d out of range", ErrInvalidFormat, v)
+	}
+	return val, nil
+}
+
+// marshalBinary encodes s into a single set of the binary format.
+func marshalBinary(s Set) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalBinary decodes the values of the single set in data.
+func unmarshalBinary(data []byte) ([]interface{}, error) {
+	d := NewDecoder(bytes.NewReader(data))
+	values := []interface{}{}
+	err := d.decode(func(val interface{}) {
+		values = append(values, val)
+	})
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: empty data", ErrInvalidFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.r.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the set", ErrInvalidFormat)
+	}
+	return values, nil
+}
+
+// MarshalBinary encodes the values of the set in the format of Encoder.
+func (s *ThreadSafeSet) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary decodes the data which is encoded by MarshalBinary and
+// replaces the values of the set. It returns an error which wraps
+// ErrInvalidFormat if the data is not valid, and the set is not changed in
+// that case.
+func (s *ThreadSafeSet) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	s.replace(values)
+	return nil
+}
+
+// GobEncode encodes the set for encoding/gob in the format of MarshalBinary.
+func (s *ThreadSafeSet) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode decodes the data which is encoded by GobEncode and replaces the
+// values of the set.
+func (s *ThreadSafeSet) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+// MarshalBinary encodes the values of the set in the format of Encoder. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	data, err := s.MarshalBinary()
+func (s *ThreadUnsafeSet) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s)
+}
+
+// UnmarshalBinary decodes the data which is encoded by MarshalBinary and
+// replaces the values of the set. It returns an error which wraps
+// ErrInvalidFormat if the data is not valid, and the set is not changed in
+// that case. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	err := s.UnmarshalBinary(data)
+func (s *ThreadUnsafeSet) UnmarshalBinary(data []byte) error {
+	values, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	s.replace(values)
+	return nil
+}
+
+// GobEncode encodes the set for encoding/gob in the format of MarshalBinary.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	err := gob.NewEncoder(w).Encode(s)
+func (s *ThreadUnsafeSet) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode decodes the data which is encoded by GobEncode and replaces the
+// values of the set. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	err := gob.NewDecoder(r).Decode(s)
+func (s *ThreadUnsafeSet) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
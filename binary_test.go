@@ -0,0 +1,223 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// binaryValues contains a value of every supported type.
+var binaryValues = []interface{}{
+	nil, true, false,
+	0, -1, math.MaxInt64, math.MinInt64,
+	int8(-128), int16(300), int32(-70000), int64(1) << 40,
+	uint(7), uint8(255), uint16(65535), uint32(1) << 31, uint64(math.MaxUint64),
+	float32(1.5), -2.25, math.Inf(1),
+	"", "str", strings.Repeat("long ", 100),
+}
+
+func TestBinary_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []interface{}
+	}{
+		{name: "Empty set"},
+		{name: "All types", values: binaryValues},
+		{name: "Same value in different types", values: []interface{}{1, int8(1), uint(1), 1.0, float32(1), "1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, st := range []setType{ThreadSafe, ThreadUnsafe} {
+				s := New(st)
+				s.Append(tc.values...)
+				data, err := s.(interface{ MarshalBinary() ([]byte, error) }).MarshalBinary()
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+
+				decoded := New(st)
+				decoded.Add("old")
+				if err := decoded.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(data); err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if !decoded.Equal(s) {
+					t.Errorf("expected %v, actual %v", s.Slice(), decoded.Slice())
+				}
+			}
+		})
+	}
+}
+
+func TestBinary_Gob(t *testing.T) {
+	type document struct {
+		Name   string
+		Safe   *ThreadSafeSet
+		Unsafe *ThreadUnsafeSet
+	}
+
+	doc := document{Name: "doc", Safe: newThreadSafeSet(), Unsafe: newThreadUnsafeSet()}
+	doc.Safe.Append(binaryValues...)
+	doc.Unsafe.Append("a", uint8(1))
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(doc); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var decoded document
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.Name != doc.Name {
+		t.Errorf("expected %v, actual %v", doc.Name, decoded.Name)
+	}
+	checkSameSet(t, "safe", decoded.Safe, doc.Safe)
+	checkSameSet(t, "unsafe", decoded.Unsafe, doc.Unsafe)
+}
+
+func TestEncoder_Stream(t *testing.T) {
+	sets := []Set{New(ThreadSafe), New(Ordered), New(InsertionOrdered), NewBitSet()}
+	sets[0].Append("a", "b")
+	sets[1].Append(3, 1, 2)
+	sets[3].Append(1, 64)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for _, s := range sets {
+		if err := enc.Encode(s); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	dec := NewDecoder(&buf)
+	for _, s := range sets {
+		decoded := New(ThreadUnsafe)
+		if err := dec.Decode(decoded); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		checkSameSet(t, "decoded", decoded, s)
+	}
+	if err := dec.Decode(New(ThreadUnsafe)); err != io.EOF {
+		t.Errorf("expected io.EOF, actual %v", err)
+	}
+}
+
+func TestEncoder_UnsupportedType(t *testing.T) {
+	type named int
+	for _, val := range []interface{}{named(1), complex(1, 2), struct{}{}, [2]int{}} {
+		s := New(ThreadUnsafe)
+		s.Append(1, val)
+		if _, err := marshalBinary(s); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType for %T, actual %v", val, err)
+		}
+	}
+}
+
+func TestEncoder_UnsupportedTypeStream(t *testing.T) {
+	bad := New(ThreadUnsafe)
+	bad.Append("a", complex(1, 2), "b", "c")
+	good := New(ThreadUnsafe)
+	good.Append("d", 1)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(bad); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("expected ErrUnsupportedType, actual %v", err)
+	}
+	if err := enc.Encode(good); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	dec := NewDecoder(&buf)
+	decoded := New(ThreadUnsafe)
+	if err := dec.Decode(decoded); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkSameSet(t, "decoded", decoded, good)
+	if err := dec.Decode(New(ThreadUnsafe)); err != io.EOF {
+		t.Errorf("expected io.EOF, actual %v", err)
+	}
+}
+
+// errWriter is an io.Writer which always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestEncoder_WriteError(t *testing.T) {
+	s := New(ThreadUnsafe)
+	s.Add("a")
+	enc := NewEncoder(errWriter{})
+	for i := 0; i < 2; i++ {
+		if err := enc.Encode(s); err != io.ErrClosedPipe {
+			t.Errorf("expected io.ErrClosedPipe, actual %v", err)
+		}
+	}
+	if err := enc.Encode(New(ThreadUnsafe)); err != io.ErrClosedPipe {
+		t.Errorf("expected io.ErrClosedPipe for the empty set, actual %v", err)
+	}
+}
+
+func TestUnmarshalBinary_TrailingDataAfterBuffer(t *testing.T) {
+	// The set fills the buffer of the Decoder exactly, so the trailing bytes
+	// are not buffered yet when the set ends.
+	s := New(ThreadUnsafe)
+	s.Add(strings.Repeat("a", 4088))
+	data, err := marshalBinary(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(data) != 4096 {
+		t.Fatalf("expected 4096 bytes, actual %d", len(data))
+	}
+
+	decoded := newThreadUnsafeSet()
+	if err := decoded.UnmarshalBinary(append(data, 0, 0)); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, actual %v", err)
+	}
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkSameSet(t, "decoded", decoded, s)
+}
+
+func TestUnmarshalBinary_Invalid(t *testing.T) {
+	valid := []byte{'S', 'E', 'T', 1, tagString, 3, 'a', 'b', 'c', tagEnd}
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty data", data: nil},
+		{name: "Short header", data: []byte{'S', 'E'}},
+		{name: "Unknown header", data: []byte{'S', 'B', 'F', 1, tagEnd}},
+		{name: "Unknown version", data: []byte{'S', 'E', 'T', 2, tagEnd}},
+		{name: "Missing end", data: valid[:len(valid)-1]},
+		{name: "Short string", data: valid[:7]},
+		{name: "Trailing data", data: append(append([]byte{}, valid...), 0)},
+		{name: "Unknown tag", data: []byte{'S', 'E', 'T', 1, 200, tagEnd}},
+		{name: "Out of range", data: []byte{'S', 'E', 'T', 1, tagUint8, 0x80, 0x02, tagEnd}},
+		{name: "Varint overflow", data: []byte{'S', 'E', 'T', 1, tagUint64, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02, tagEnd}},
+		{name: "Huge string length", data: []byte{'S', 'E', 'T', 1, tagString, 0xff, 0xff, 0xff, 0xff, 0x0f, 'a', tagEnd}},
+		{name: "Short float", data: []byte{'S', 'E', 'T', 1, tagFloat64, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadUnsafeSet()
+			s.Add("old")
+			if err := s.UnmarshalBinary(tc.data); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("expected ErrInvalidFormat, actual %v", err)
+			}
+			if !reflect.DeepEqual(s.Slice(), []interface{}{"old"}) {
+				t.Errorf("expected unchanged set, actual %v", s.Slice())
+			}
+		})
+	}
+}
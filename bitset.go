@@ -0,0 +1,573 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// BitSet is a set type for the dense non-negative integers. Every value takes a
+// single bit, so it needs much less memory than the map based sets when the
+// values are small. It grows automatically while adding the values. It does
+// not provide the thread-safety.
+//
+// The values are stored as uint, and they are returned as uint from Slice,
+// Each, All and Pop. Add, Append and Remove accept the values of any integer
+// type and convert them into uint. Adding a negative number or a value which is
+// not an integer panics.
+//
+// Contains, Intersection, Difference and the relations such as Equal compare
+// the values with their types like the other sets, so only the uint values
+// match: a *BitSet which contains 1 contains uint(1) but not int(1), and it is
+// not equal to a set which contains int(1). Therefore, they give the same
+// results when the sets are swapped. The only exception is *RoaringBitmap,
+// whose values are compared by their integer values like RoaringBitmap does.
+// Union and SymmetricDifference convert all values of the other set into uint,
+// and they panic if a value is not a non-negative integer. The binary
+// operations work on the words, and the other sets are converted into a
+// *BitSet first.
+type BitSet struct {
+	words []uint64
+}
+
+// wordSize is the number of bits in a word of BitSet.
+const wordSize = 64
+
+// NewBitSet creates a new empty *BitSet.
+//
+//	s := set.NewBitSet()
+func NewBitSet() *BitSet {
+	return &BitSet{}
+}
+
+// NewBitSetOf creates a new *BitSet which contains the given values. The Go
+// syntax of BitSet, which is printed by %#v, uses NewBitSetOf.
+//
+//	s := set.NewBitSetOf(1, 5, 64)
+func NewBitSetOf(values ...uint) *BitSet {
+	s := NewBitSet()
+	for _, i := range values {
+		s.AddUint(i)
+	}
+	return s
+}
+
+// AddUint adds i into the set. The set grows if i is out of its capacity.
+//
+// Example:
+//	s.AddUint(12)
+func (s *BitSet) AddUint(i uint) {
+	w := i / wordSize
+	if w >= uint(len(s.words)) {
+		s.grow(w + 1)
+	}
+	s.words[w] |= 1 << (i % wordSize)
+}
+
+// RemoveUint deletes i from the set.
+//
+// Example:
+//	s.RemoveUint(12)
+func (s *BitSet) RemoveUint(i uint) {
+	w := i / wordSize
+	if w < uint(len(s.words)) {
+		s.words[w] &^= 1 << (i % wordSize)
+	}
+}
+
+// ContainsUint checks whether i exists in the set.
+//
+// Example:
+//	exist := s.ContainsUint(12)
+func (s *BitSet) ContainsUint(i uint) bool {
+	w := i / wordSize
+	return w < uint(len(s.words)) && s.words[w]&(1<<(i%wordSize)) != 0
+}
+
+// NextSet returns the smallest value in the set which is greater than or equal
+// to i with true. If there is no such value, it returns false. It can be used
+// for walking the set without a callback.
+//
+// Example:
+//	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+//		fmt.Println(i)
+//	}
+func (s *BitSet) NextSet(i uint) (uint, bool) {
+	w := i / wordSize
+	if w >= uint(len(s.words)) {
+		return 0, false
+	}
+	word := s.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < uint(len(s.words)); w++ {
+		if s.words[w] != 0 {
+			return w*wordSize + uint(bits.TrailingZeros64(s.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// Add adds a new value to set. The value must be a non-negative integer,
+// otherwise Add panics. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	s.Add(12)
+func (s *BitSet) Add(val interface{}) {
+	i, ok := toBitIndex(val)
+	if !ok {
+		panic(fmt.Sprintf("set: BitSet only accepts non-negative integers, got %v (%T)", val, val))
+	}
+	s.AddUint(i)
+}
+
+// Append adds multiple values into set. All values must be non-negative
+// integers, otherwise Append panics. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Append(1, 2, 3, 4)
+func (s *BitSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Remove(2)
+func (s *BitSet) Remove(val interface{}) {
+	if i, ok := toBitIndex(val); ok {
+		s.RemoveUint(i)
+	}
+}
+
+// Contains checks the value whether exists in the set. It returns false if the
+// value is not a uint. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	exist := s.Contains(uint(1))
+func (s *BitSet) Contains(val interface{}) bool {
+	i, ok := val.(uint)
+	return ok && s.ContainsUint(i)
+}
+
+// Size returns the length of the set which means that number of value of the set.
+// It counts the bits of the words. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	size := s.Size()
+func (s *BitSet) Size() uint {
+	var size uint
+	for _, word := range s.words {
+		size += uint(bits.OnesCount64(word))
+	}
+	return size
+}
+
+// Pop removes the smallest value from the set and returns it as uint. If there
+// is no element in set, it returns nil. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	min := s.Pop()
+func (s *BitSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the smallest value from the set and returns it as uint with
+// true. If there is no element in set, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	min, ok := s.TryPop()
+func (s *BitSet) TryPop() (interface{}, bool) {
+	i, ok := s.NextSet(0)
+	if !ok {
+		return nil, false
+	}
+	s.RemoveUint(i)
+	return i, true
+}
+
+// Peek returns the smallest value as uint without removing it. If there is no
+// element in set, it returns nil. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	min := s.Peek()
+func (s *BitSet) Peek() interface{} {
+	if i, ok := s.NextSet(0); ok {
+		return i
+	}
+	return nil
+}
+
+// Clear removes everything from the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Clear()
+func (s *BitSet) Clear() {
+	s.words = nil
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	empty := s.Empty()
+func (s *BitSet) Empty() bool {
+	for _, word := range s.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice returns the elements of the set as a slice of uint values in ascending
+// order. It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	setSlice := s.Slice()
+func (s *BitSet) Slice() []interface{} {
+	values := make([]interface{}, 0, s.Size())
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every value in the set in ascending order until fn returns
+// false. The values are given as uint. fn may add or remove the values which
+// are greater than the current value, and they are visited accordingly. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *BitSet) Each(fn func(val interface{}) bool) {
+	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+		if !fn(i) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set in ascending order. The
+// values are given as uint. It follows the same rules with Each. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *BitSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new *BitSet that contains the values which satisfy pred. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(uint)%2 == 0 })
+func (s *BitSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, NewBitSet(), pred)
+}
+
+// Map returns a new *BitSet that contains the results of fn for every value in
+// the set. fn must return non-negative integers, otherwise Map panics. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	doubles := s.Map(func(val interface{}) interface{} { return val.(uint) * 2 })
+func (s *BitSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, NewBitSet(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value in ascending order, starting with init. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	sum := s.Reduce(uint(0), func(acc, val interface{}) interface{} { return acc.(uint) + val.(uint) })
+func (s *BitSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new *BitSets. The first one contains the
+// values which satisfy pred and the second one contains the others. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(uint)%2 == 0 })
+func (s *BitSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, NewBitSet(), NewBitSet(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	hasBig := s.AnyMatch(func(val interface{}) bool { return val.(uint) > 1000 })
+func (s *BitSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	allSmall := s.AllMatch(func(val interface{}) bool { return val.(uint) < 1000 })
+func (s *BitSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evenCount := s.CountIf(func(val interface{}) bool { return val.(uint)%2 == 0 })
+func (s *BitSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *BitSet that contains all items from the receiver set and
+// all items from the given set. The given set can be any Set implementation,
+// but all of its values must be non-negative integers, otherwise Union panics.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *BitSet) Union(set Set) Set {
+	o := toBitSet(set)
+	long, short := s.words, o.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	words := append([]uint64(nil), long...)
+	for i, word := range short {
+		words[i] |= word
+	}
+	return &BitSet{words: words}
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *BitSet that stores the common ones. The values of the given set which are
+// not uint are ignored. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *BitSet) Intersection(set Set) Set {
+	o, _ := asUintBitSet(set)
+	words := make([]uint64, min(len(s.words), len(o.words)))
+	for i := range words {
+		words[i] = s.words[i] & o.words[i]
+	}
+	return &BitSet{words: words}
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *BitSet. The values of the given set which are not uint are ignored. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *BitSet) Difference(set Set) Set {
+	o, _ := asUintBitSet(set)
+	words := append([]uint64(nil), s.words...)
+	for i := 0; i < len(words) && i < len(o.words); i++ {
+		words[i] &^= o.words[i]
+	}
+	return &BitSet{words: words}
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *BitSet) IsSubset(set Set) bool {
+	o, _ := asUintBitSet(set)
+	return s.isSubset(o)
+}
+
+// isSubset returns true if all bits of s are set in o.
+func (s *BitSet) isSubset(o *BitSet) bool {
+	for i, word := range s.words {
+		if i >= len(o.words) {
+			if word != 0 {
+				return false
+			}
+			continue
+		}
+		if word&^o.words[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *BitSet) IsSuperset(set Set) bool {
+	if set.Size() > s.Size() {
+		return false
+	}
+	if o, ok := asUintBitSet(set); ok {
+		return o.isSubset(s)
+	}
+	return isSubset(set, s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *BitSet) IsDisjoint(set Set) bool {
+	o, _ := asUintBitSet(set)
+	for i := 0; i < len(s.words) && i < len(o.words); i++ {
+		if s.words[i]&o.words[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks whether both sets contain exactly the same values. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *BitSet) Equal(set Set) bool {
+	if set.Size() != s.Size() {
+		return false
+	}
+	if o, ok := asUintBitSet(set); ok {
+		return s.isSubset(o) && o.isSubset(s)
+	}
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *BitSet that contains from two sets, but
+// not the items are present in both sets. All values of the given set must be
+// non-negative integers, otherwise SymmetricDifference panics. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *BitSet) SymmetricDifference(set Set) Set {
+	o := toBitSet(set)
+	long, short := s.words, o.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	words := append([]uint64(nil), long...)
+	for i, word := range short {
+		words[i] ^= word
+	}
+	return &BitSet{words: words}
+}
+
+// grow extends the words of the set to n words.
+func (s *BitSet) grow(n uint) {
+	if n <= uint(cap(s.words)) {
+		s.words = s.words[:n]
+		return
+	}
+	words := make([]uint64, n, max(n, 2*uint(cap(s.words))))
+	copy(words, s.words)
+	s.words = words
+}
+
+// toBitSet returns s if it is a *BitSet. Otherwise, it copies the values of s
+// into a new *BitSet. It panics if any value of s is not a non-negative integer.
+func toBitSet(s Set) *BitSet {
+	if b, ok := s.(*BitSet); ok {
+		return b
+	}
+	b := NewBitSet()
+	s.Each(func(val interface{}) bool {
+		b.Add(val)
+		return true
+	})
+	return b
+}
+
+// asUintBitSet returns s if it is a *BitSet. Otherwise, it copies the uint
+// values of s into a new *BitSet. It returns false if any value of s is not a
+// uint, because the other integer types are different values for the other
+// sets, and int(1) and uint(1) would be the same bit. The values of a
+// *RoaringBitmap are copied by their integer values, because RoaringBitmap
+// compares the values of a *BitSet in the same way.
+func asUintBitSet(s Set) (*BitSet, bool) {
+	switch v := s.(type) {
+	case *BitSet:
+		return v, true
+	case *RoaringBitmap:
+		b := NewBitSet()
+		v.Each(func(val interface{}) bool {
+			b.AddUint(uint(val.(uint32)))
+			return true
+		})
+		return b, true
+	}
+	b := NewBitSet()
+	all := true
+	s.Each(func(val interface{}) bool {
+		if i, ok := val.(uint); ok {
+			b.AddUint(i)
+		} else {
+			all = false
+		}
+		return true
+	})
+	return b, all
+}
+
+// toBitIndex converts val into uint if it is a non-negative integer.
+func toBitIndex(val interface{}) (uint, bool) {
+	switch v := val.(type) {
+	case int:
+		return uint(v), v >= 0
+	case int8:
+		return uint(v), v >= 0
+	case int16:
+		return uint(v), v >= 0
+	case int32:
+		return uint(v), v >= 0
+	case int64:
+		return uint(v), v >= 0
+	case uint:
+		return v, true
+	case uint8:
+		return uint(v), true
+	case uint16:
+		return uint(v), true
+	case uint32:
+		return uint(v), true
+	case uint64:
+		return uint(v), true
+	case uintptr:
+		return uint(v), true
+	}
+	return 0, false
+}
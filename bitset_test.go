@@ -0,0 +1,211 @@
+package set
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBitSet_AddRemove(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		removes  []interface{}
+		expSlice []interface{}
+	}{
+		{
+			name:     "Empty set",
+			expSlice: []interface{}{},
+		},
+		{
+			name:     "Different integer types",
+			values:   []interface{}{5, uint8(3), int64(200), uint(0), 3},
+			expSlice: []interface{}{uint(0), uint(3), uint(5), uint(200)},
+		},
+		{
+			name:     "Remove values",
+			values:   []interface{}{1, 64, 65, 1000},
+			removes:  []interface{}{64, 5000, -1, "str", uint16(1)},
+			expSlice: []interface{}{uint(65), uint(1000)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewBitSet()
+			s.Append(tc.values...)
+			for _, val := range tc.removes {
+				s.Remove(val)
+			}
+
+			if values := s.Slice(); !reflect.DeepEqual(values, tc.expSlice) {
+				t.Errorf("expected %v, actual %v", tc.expSlice, values)
+			}
+			if s.Size() != uint(len(tc.expSlice)) {
+				t.Errorf("expected size %v, actual size %v", len(tc.expSlice), s.Size())
+			}
+			if s.Empty() != (len(tc.expSlice) == 0) {
+				t.Errorf("expected empty %v", len(tc.expSlice) == 0)
+			}
+			for _, val := range tc.expSlice {
+				if !s.Contains(val) {
+					t.Errorf("%v not exist in set", val)
+				}
+				if s.Contains(int(val.(uint))) {
+					t.Errorf("int(%v) exists in set", val)
+				}
+			}
+		})
+	}
+}
+
+func TestBitSet_InvalidValues(t *testing.T) {
+	s := NewBitSet()
+	if s.Contains(-1) || s.Contains("1") || s.Contains(1.0) {
+		t.Errorf("expected false for invalid values")
+	}
+	for _, val := range []interface{}{-1, "1", 1.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %v", val)
+				}
+			}()
+			s.Add(val)
+		}()
+	}
+}
+
+func TestBitSet_NextSetAndPop(t *testing.T) {
+	s := NewBitSet()
+	s.Append(3, 64, 130)
+
+	var values []uint
+	for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+		values = append(values, i)
+	}
+	if !reflect.DeepEqual(values, []uint{3, 64, 130}) {
+		t.Errorf("expected [3 64 130], actual %v", values)
+	}
+	if _, ok := s.NextSet(131); ok {
+		t.Errorf("expected no value after 130")
+	}
+	if val := s.Peek(); val != uint(3) {
+		t.Errorf("expected 3, actual %v", val)
+	}
+	if val := s.Pop(); val != uint(3) || s.Contains(uint(3)) {
+		t.Errorf("expected 3 is popped, actual %v", val)
+	}
+	s.Clear()
+	if val, ok := s.TryPop(); ok || val != nil {
+		t.Errorf("expected nil and false, actual %v and %v", val, ok)
+	}
+}
+
+func TestBitSet_SetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		a, b := NewBitSet(), NewBitSet()
+		ma, mb := newThreadUnsafeSet(), newThreadUnsafeSet()
+		for j := 0; j < 100; j++ {
+			x, y := uint(r.Intn(300)), uint(r.Intn(150))
+			a.AddUint(x)
+			ma.Add(x)
+			b.AddUint(y)
+			mb.Add(y)
+		}
+
+		checkSameSet(t, "union", a.Union(b), ma.Union(mb))
+		checkSameSet(t, "intersection", a.Intersection(b), ma.Intersection(mb))
+		checkSameSet(t, "difference", a.Difference(b), ma.Difference(mb))
+		checkSameSet(t, "reverse difference", b.Difference(a), mb.Difference(ma))
+		checkSameSet(t, "symmetric difference", a.SymmetricDifference(b), ma.SymmetricDifference(mb))
+		checkSameSet(t, "mixed union", a.Union(mb), ma.Union(mb))
+		checkSameSet(t, "mixed intersection", a.Intersection(mb), ma.Intersection(mb))
+		checkSameSet(t, "mixed difference", a.Difference(mb), ma.Difference(mb))
+		checkSameSet(t, "mixed symmetric difference", a.SymmetricDifference(mb), ma.SymmetricDifference(mb))
+		if a.IsDisjoint(b) != ma.IsDisjoint(mb) || a.IsDisjoint(mb) != ma.IsDisjoint(mb) {
+			t.Errorf("unexpected disjoint result")
+		}
+	}
+}
+
+func TestBitSet_Relations(t *testing.T) {
+	a, b := NewBitSet(), NewBitSet()
+	a.Append(1, 2)
+	b.Append(1, 2, 200)
+	if !a.IsSubset(b) || a.IsSuperset(b) || !b.IsSuperset(a) || b.IsSubset(a) || a.Equal(b) {
+		t.Errorf("unexpected subset relation")
+	}
+	b.Remove(200)
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("expected equal sets")
+	}
+}
+
+func TestBitSet_SymmetricRelations(t *testing.T) {
+	b := NewBitSet()
+	b.Append(1, 2)
+	roaring := func(values ...uint32) Set {
+		r := NewRoaringBitmap()
+		for _, val := range values {
+			r.AddUint32(val)
+		}
+		return r
+	}
+	testCases := []struct {
+		name string
+		set  Set
+	}{
+		{name: "Same ints", set: NewOf(ThreadUnsafe, 1, 2)},
+		{name: "Subset ints", set: NewOf(ThreadUnsafe, 1)},
+		{name: "Superset ints", set: NewOf(ThreadUnsafe, 1, 2, 3)},
+		{name: "Mixed integers", set: NewOf(ThreadUnsafe, 1, int64(1), uint(1))},
+		{name: "Mixed with all uints", set: NewOf(ThreadUnsafe, 1, uint(1), uint(2))},
+		{name: "Same uints", set: NewOf(ThreadUnsafe, uint(1), uint(2))},
+		{name: "Subset uints", set: NewOf(ThreadUnsafe, uint(1))},
+		{name: "Strings", set: NewOf(ThreadUnsafe, uint(1), "a")},
+		{name: "Same roaring", set: roaring(1, 2)},
+		{name: "Subset roaring", set: roaring(2)},
+		{name: "Superset roaring", set: roaring(1, 2, 70000)},
+		{name: "Disjoint roaring", set: roaring(3)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := tc.set
+			if b.Equal(o) != o.Equal(b) {
+				t.Errorf("Equal: %v and %v", b.Equal(o), o.Equal(b))
+			}
+			if b.IsSubset(o) != o.IsSuperset(b) {
+				t.Errorf("IsSubset and IsSuperset: %v and %v", b.IsSubset(o), o.IsSuperset(b))
+			}
+			if b.IsSuperset(o) != o.IsSubset(b) {
+				t.Errorf("IsSuperset and IsSubset: %v and %v", b.IsSuperset(o), o.IsSubset(b))
+			}
+			if b.IsDisjoint(o) != o.IsDisjoint(b) {
+				t.Errorf("IsDisjoint: %v and %v", b.IsDisjoint(o), o.IsDisjoint(b))
+			}
+			if b.Equal(o) != (b.IsSubset(o) && b.IsSuperset(o)) {
+				t.Errorf("Equal is %v, but IsSubset and IsSuperset are %v and %v", b.Equal(o), b.IsSubset(o), b.IsSuperset(o))
+			}
+			if b.Intersection(o).Size() != o.Intersection(b).Size() {
+				t.Errorf("Intersection: %v and %v", b.Intersection(o).Size(), o.Intersection(b).Size())
+			}
+		})
+	}
+}
+
+// checkSameSet checks whether s contains exactly the values of exp.
+func checkSameSet(t *testing.T, name string, s, exp Set) {
+	t.Helper()
+	if s.Size() != exp.Size() {
+		t.Errorf("%s: expected size %v, actual size %v", name, exp.Size(), s.Size())
+	}
+	exp.Each(func(val interface{}) bool {
+		if !s.Contains(val) {
+			t.Errorf("%s: expected %v, but not exists", name, val)
+		}
+		return true
+	})
+}
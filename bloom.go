@@ -0,0 +1,389 @@
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// ErrIncompatibleFilter is returned when two probabilistic filters or sketches
+// which are created with different parameters are combined.
+var ErrIncompatibleFilter = errors.New("set: incompatible filters")
+
+// BloomFilter is a probabilistic set which keeps only a fixed number of bits
+// for any number of values. Contains never returns false for an added value,
+// but it may return true for a value which is not added. The rate of the false
+// positives grows with the number of the added values, so the filter is sized
+// from the expected number of the values and the target rate. The values
+// cannot be removed or listed; CountingBloomFilter supports Remove with more
+// memory. It does not provide the thread-safety.
+//
+// The values are hashed independently of the process, so the serialized
+// filters can be read by the other processes. The pointers, the channels and
+// the values which contain them are compared by their identity, so they cannot
+// be hashed in that way: the methods panic with an error which wraps
+// ErrUnsupportedType for them.
+type BloomFilter struct {
+	words []uint64
+	m     uint64
+	k     uint32
+}
+
+// NewBloomFilter creates a new empty *BloomFilter for n values with the false
+// positive rate fpRate. fpRate must be between 0 and 1, exclusively.
+//
+//	f := set.NewBloomFilter(1000000, 0.01)
+func NewBloomFilter(n uint, fpRate float64) *BloomFilter {
+	m, k := bloomParams(n, fpRate)
+	return &BloomFilter{words: make([]uint64, (m+wordSize-1)/wordSize), m: m, k: k}
+}
+
+// bloomParams returns the optimal number of bits and hash functions for n
+// values with the false positive rate fpRate.
+func bloomParams(n uint, fpRate float64) (uint64, uint32) {
+	if !(fpRate > 0 && fpRate < 1) {
+		panic(fmt.Sprintf("set: false positive rate must be between 0 and 1, got %v", fpRate))
+	}
+	nf := float64(max(n, 1))
+	m := uint64(math.Ceil(-nf * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint32(math.Round(float64(m) / nf * math.Ln2))
+	return m, max(k, 1)
+}
+
+// bloomLocations calls fn with the k locations of val in a filter of m
+// locations until fn returns false. It uses the double hashing, so val is
+// hashed only once. It panics with an error which wraps ErrUnsupportedType if
+// val cannot be hashed.
+func bloomLocations(val interface{}, m uint64, k uint32, fn func(i uint64) bool) bool {
+	h1, h2 := mustHashValue(val)
+	h2 |= 1 // Avoids a zero step which visits a single location.
+	for i := uint64(0); i < uint64(k); i++ {
+		if !fn((h1 + i*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// estimateCount estimates the number of the values in a filter of m locations
+// and k hash functions which has x locations set.
+func estimateCount(m uint64, k uint32, x uint64) uint {
+	if x >= m {
+		x = m - 1
+	}
+	return uint(math.Round(-float64(m) / float64(k) * math.Log(1-float64(x)/float64(m))))
+}
+
+// Add adds val into the filter.
+//
+// Example:
+//	f.Add("https://example.com")
+func (f *BloomFilter) Add(val interface{}) {
+	bloomLocations(val, f.m, f.k, func(i uint64) bool {
+		f.words[i/wordSize] |= 1 << (i % wordSize)
+		return true
+	})
+}
+
+// Append adds multiple values into the filter.
+//
+// Example:
+//	f.Append("a", "b", "c")
+func (f *BloomFilter) Append(val ...interface{}) {
+	for _, v := range val {
+		f.Add(v)
+	}
+}
+
+// Contains checks whether val may exist in the filter. It returns false only
+// if val is not added.
+//
+// Example:
+//	exist := f.Contains("https://example.com")
+func (f *BloomFilter) Contains(val interface{}) bool {
+	return bloomLocations(val, f.m, f.k, func(i uint64) bool {
+		return f.words[i/wordSize]&(1<<(i%wordSize)) != 0
+	})
+}
+
+// Clear removes all values from the filter.
+//
+// Example:
+//	f.Clear()
+func (f *BloomFilter) Clear() {
+	clear(f.words)
+}
+
+// Empty checks whether no value is added into the filter.
+//
+// Example:
+//	empty := f.Empty()
+func (f *BloomFilter) Empty() bool {
+	for _, w := range f.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedSize estimates the number of the distinct values which are added
+// into the filter from the number of the set bits.
+//
+// Example:
+//	n := f.EstimatedSize()
+func (f *BloomFilter) EstimatedSize() uint {
+	var x uint64
+	for _, w := range f.words {
+		x += uint64(bits.OnesCount64(w))
+	}
+	return estimateCount(f.m, f.k, x)
+}
+
+// Union returns a new filter which contains the values of both filters. The
+// filters must be created with the same parameters, otherwise it returns
+// ErrIncompatibleFilter.
+//
+// Example:
+//	u, err := f.Union(other)
+func (f *BloomFilter) Union(o *BloomFilter) (*BloomFilter, error) {
+	if f.m != o.m || f.k != o.k {
+		return nil, fmt.Errorf("%w: %d bits and %d hashes, %d bits and %d hashes",
+			ErrIncompatibleFilter, f.m, f.k, o.m, o.k)
+	}
+	u := &BloomFilter{words: make([]uint64, len(f.words)), m: f.m, k: f.k}
+	for i := range u.words {
+		u.words[i] = f.words[i] | o.words[i]
+	}
+	return u, nil
+}
+
+// The headers of the serialized filters. The last byte is the version of the
+// format.
+var (
+	bloomMagic         = [4]byte{'S', 'B', 'F', 1}
+	countingBloomMagic = [4]byte{'S', 'C', 'F', 1}
+)
+
+// appendFilterHeader appends the header of a serialized filter into b.
+func appendFilterHeader(b []byte, magic [4]byte, m uint64, k uint32) []byte {
+	b = append(b, magic[:]...)
+	b = binary.LittleEndian.AppendUint64(b, m)
+	return binary.LittleEndian.AppendUint32(b, k)
+}
+
+// readFilterHeader reads the header of a serialized filter and returns its
+// parameters with the rest of the data. Every location of a filter needs at
+// least one bit of the data, so the larger m values are rejected before the
+// decoders compute their sizes with it.
+func readFilterHeader(data []byte, magic [4]byte) (uint64, uint32, []byte, error) {
+	if len(data) < 16 {
+		return 0, 0, nil, fmt.Errorf("%w: header is too short", ErrInvalidFormat)
+	}
+	if [4]byte(data[:4]) != magic {
+		return 0, 0, nil, fmt.Errorf("%w: unknown header %q", ErrInvalidFormat, data[:4])
+	}
+	m := binary.LittleEndian.Uint64(data[4:])
+	k := binary.LittleEndian.Uint32(data[12:])
+	if m == 0 || k == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: %d bits and %d hashes", ErrInvalidFormat, m, k)
+	}
+	if m/8 > uint64(len(data)-16) {
+		return 0, 0, nil, fmt.Errorf("%w: %d bytes for %d locations", ErrInvalidFormat, len(data)-16, m)
+	}
+	return m, k, data[16:], nil
+}
+
+// MarshalBinary encodes the filter with its parameters.
+//
+// Example:
+//	data, err := f.MarshalBinary()
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	b := appendFilterHeader(make([]byte, 0, 16+8*len(f.words)), bloomMagic, f.m, f.k)
+	for _, w := range f.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes the data which is encoded by MarshalBinary and
+// replaces the filter. It returns an error which wraps ErrInvalidFormat if the
+// data is not valid.
+//
+// Example:
+//	err := f.UnmarshalBinary(data)
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, rest, err := readFilterHeader(data, bloomMagic)
+	if err != nil {
+		return err
+	}
+	n := (m + wordSize - 1) / wordSize
+	if uint64(len(rest)) != 8*n {
+		return fmt.Errorf("%w: %d bytes for %d bits", ErrInvalidFormat, len(rest), m)
+	}
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(rest[8*i:])
+	}
+	if r := m % wordSize; r != 0 && words[n-1]>>r != 0 {
+		return fmt.Errorf("%w: bits out of the range", ErrInvalidFormat)
+	}
+	f.words, f.m, f.k = words, m, k
+	return nil
+}
+
+// CountingBloomFilter is a BloomFilter which keeps a counter instead of a bit
+// for every location, so the values can be removed. It uses 8 times more
+// memory than BloomFilter. A counter stops at 255 and it is not decremented
+// after that, so the values are never lost. Removing a value which is not
+// added may remove the other values, so only the added values should be
+// removed. It does not provide the thread-safety.
+type CountingBloomFilter struct {
+	counters []uint8
+	k        uint32
+}
+
+// NewCountingBloomFilter creates a new empty *CountingBloomFilter for n values
+// with the false positive rate fpRate. fpRate must be between 0 and 1,
+// exclusively.
+//
+//	f := set.NewCountingBloomFilter(10000, 0.01)
+func NewCountingBloomFilter(n uint, fpRate float64) *CountingBloomFilter {
+	m, k := bloomParams(n, fpRate)
+	return &CountingBloomFilter{counters: make([]uint8, m), k: k}
+}
+
+// Add adds val into the filter.
+//
+// Example:
+//	f.Add("https://example.com")
+func (f *CountingBloomFilter) Add(val interface{}) {
+	bloomLocations(val, uint64(len(f.counters)), f.k, func(i uint64) bool {
+		if f.counters[i] < math.MaxUint8 {
+			f.counters[i]++
+		}
+		return true
+	})
+}
+
+// Append adds multiple values into the filter.
+//
+// Example:
+//	f.Append("a", "b", "c")
+func (f *CountingBloomFilter) Append(val ...interface{}) {
+	for _, v := range val {
+		f.Add(v)
+	}
+}
+
+// Remove deletes val from the filter. It does nothing if the filter does not
+// contain val.
+//
+// Example:
+//	f.Remove("https://example.com")
+func (f *CountingBloomFilter) Remove(val interface{}) {
+	if !f.Contains(val) {
+		return
+	}
+	bloomLocations(val, uint64(len(f.counters)), f.k, func(i uint64) bool {
+		if f.counters[i] < math.MaxUint8 {
+			f.counters[i]--
+		}
+		return true
+	})
+}
+
+// Contains checks whether val may exist in the filter. It returns false only
+// if val is not added or it is removed.
+//
+// Example:
+//	exist := f.Contains("https://example.com")
+func (f *CountingBloomFilter) Contains(val interface{}) bool {
+	return bloomLocations(val, uint64(len(f.counters)), f.k, func(i uint64) bool {
+		return f.counters[i] != 0
+	})
+}
+
+// Clear removes all values from the filter.
+//
+// Example:
+//	f.Clear()
+func (f *CountingBloomFilter) Clear() {
+	clear(f.counters)
+}
+
+// Empty checks whether the filter has no value.
+//
+// Example:
+//	empty := f.Empty()
+func (f *CountingBloomFilter) Empty() bool {
+	for _, c := range f.counters {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedSize estimates the number of the distinct values in the filter
+// from the number of the non-zero counters.
+//
+// Example:
+//	n := f.EstimatedSize()
+func (f *CountingBloomFilter) EstimatedSize() uint {
+	var x uint64
+	for _, c := range f.counters {
+		if c != 0 {
+			x++
+		}
+	}
+	return estimateCount(uint64(len(f.counters)), f.k, x)
+}
+
+// Union returns a new filter which contains the values of both filters. The
+// counters are summed, so the values of both filters can be removed from the
+// result. The filters must be created with the same parameters, otherwise it
+// returns ErrIncompatibleFilter.
+//
+// Example:
+//	u, err := f.Union(other)
+func (f *CountingBloomFilter) Union(o *CountingBloomFilter) (*CountingBloomFilter, error) {
+	if len(f.counters) != len(o.counters) || f.k != o.k {
+		return nil, fmt.Errorf("%w: %d counters and %d hashes, %d counters and %d hashes",
+			ErrIncompatibleFilter, len(f.counters), f.k, len(o.counters), o.k)
+	}
+	u := &CountingBloomFilter{counters: make([]uint8, len(f.counters)), k: f.k}
+	for i := range u.counters {
+		u.counters[i] = uint8(min(uint(f.counters[i])+uint(o.counters[i]), math.MaxUint8))
+	}
+	return u, nil
+}
+
+// MarshalBinary encodes the filter with its parameters.
+//
+// Example:
+//	data, err := f.MarshalBinary()
+func (f *CountingBloomFilter) MarshalBinary() ([]byte, error) {
+	b := appendFilterHeader(make([]byte, 0, 16+len(f.counters)), countingBloomMagic, uint64(len(f.counters)), f.k)
+	return append(b, f.counters...), nil
+}
+
+// UnmarshalBinary decodes the data which is encoded by MarshalBinary and
+// replaces the filter. It returns an error which wraps ErrInvalidFormat if the
+// data is not valid.
+//
+// Example:
+//	err := f.UnmarshalBinary(data)
+func (f *CountingBloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, rest, err := readFilterHeader(data, countingBloomMagic)
+	if err != nil {
+		return err
+	}
+	if uint64(len(rest)) != m {
+		return fmt.Errorf("%w: %d bytes for %d counters", ErrInvalidFormat, len(rest), m)
+	}
+	f.counters, f.k = append([]uint8(nil), rest...), k
+	return nil
+}
@@ -0,0 +1,383 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBloomFilter_Contains(t *testing.T) {
+	testCases := []struct {
+		name   string
+		n      uint
+		fpRate float64
+	}{
+		{name: "Small filter", n: 100, fpRate: 0.1},
+		{name: "Medium filter", n: 10000, fpRate: 0.01},
+		{name: "Zero expected values", n: 0, fpRate: 0.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewBloomFilter(tc.n, tc.fpRate)
+			if !f.Empty() {
+				t.Errorf("new filter is not empty")
+			}
+			for i := uint(0); i < tc.n; i++ {
+				f.Add(fmt.Sprintf("url-%d", i))
+			}
+			for i := uint(0); i < tc.n; i++ {
+				if !f.Contains(fmt.Sprintf("url-%d", i)) {
+					t.Fatalf("added value url-%d is not found", i)
+				}
+			}
+
+			if tc.n == 0 {
+				return
+			}
+			var falsePositives int
+			const tries = 10000
+			for i := 0; i < tries; i++ {
+				if f.Contains(fmt.Sprintf("other-%d", i)) {
+					falsePositives++
+				}
+			}
+			if rate := float64(falsePositives) / tries; rate > 2*tc.fpRate {
+				t.Errorf("expected false positive rate around %v, actual %v", tc.fpRate, rate)
+			}
+			if est := f.EstimatedSize(); math.Abs(float64(est)-float64(tc.n)) > 0.1*float64(tc.n) {
+				t.Errorf("expected estimated size around %v, actual %v", tc.n, est)
+			}
+
+			f.Clear()
+			if !f.Empty() || f.EstimatedSize() != 0 {
+				t.Errorf("filter is not empty after Clear")
+			}
+		})
+	}
+}
+
+func TestBloomFilter_ValueTypes(t *testing.T) {
+	f := NewBloomFilter(100, 0.001)
+	f.Append(1, "1", true, 2.5, struct{ a int }{3})
+	for _, val := range []interface{}{1, "1", true, 2.5, struct{ a int }{3}} {
+		if !f.Contains(val) {
+			t.Errorf("%#v is not found", val)
+		}
+	}
+	for _, val := range []interface{}{int64(1), "2", false, float32(2.5), struct{ a int }{4}} {
+		if f.Contains(val) {
+			t.Errorf("%#v is found", val)
+		}
+	}
+}
+
+func TestHashValue_CompositeValues(t *testing.T) {
+	type pair struct {
+		Key interface{}
+		Val float64
+	}
+	testCases := []struct {
+		name    string
+		a, b    interface{}
+		expSame bool
+	}{
+		{name: "Same struct", a: pair{Key: "a", Val: 1}, b: pair{Key: "a", Val: 1}, expSame: true},
+		{name: "Negative zero field", a: pair{Key: 1, Val: math.Copysign(0, -1)}, b: pair{Key: 1}, expSame: true},
+		{name: "Interface types", a: pair{Key: 1}, b: pair{Key: int64(1)}},
+		{name: "Nil interface", a: pair{}, b: pair{Key: 0}},
+		{name: "String boundaries", a: [2]string{"ab", "c"}, b: [2]string{"a", "bc"}},
+		{name: "Complex negative zero", a: complex(math.Copysign(0, -1), 1), b: complex(0, 1), expSame: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if same := (tc.a == tc.b); same != tc.expSame {
+				t.Fatalf("expected == to be %v", tc.expSame)
+			}
+			a1, a2 := mustHashValue(tc.a)
+			b1, b2 := mustHashValue(tc.b)
+			if same := a1 == b1 && a2 == b2; same != tc.expSame {
+				t.Errorf("expected same hashes %v, actual %v", tc.expSame, same)
+			}
+		})
+	}
+}
+
+func TestHashValue_Unsupported(t *testing.T) {
+	type node struct {
+		name string
+		next *node
+	}
+	n := &node{name: "a"}
+	for _, val := range []interface{}{n, node{name: "b", next: n}, make(chan int), [1]interface{}{n}} {
+		if _, _, err := hashValue(val); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType for %T, actual %v", val, err)
+		}
+
+		c := NewCuckooFilter(16, 16)
+		if err := c.Add(val); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType from CuckooFilter for %T, actual %v", val, err)
+		}
+		func() {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, ErrUnsupportedType) {
+					t.Errorf("expected panic with ErrUnsupportedType for %T, actual %v", val, err)
+				}
+			}()
+			NewBloomFilter(10, 0.01).Add(val)
+		}()
+	}
+
+	// A pointer would be hashed by its pointee, so changing the pointee would
+	// make Contains return false for an added value.
+	n.name = "changed"
+	if _, _, err := hashValue(n); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType after changing the pointee, actual %v", err)
+	}
+}
+
+func TestHashValue_NegativeZero(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	testCases := []struct {
+		name string
+		val  interface{}
+		zero interface{}
+	}{
+		{name: "float64", val: negZero, zero: 0.0},
+		{name: "float32", val: float32(negZero), zero: float32(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h1, h2 := mustHashValue(tc.val)
+			z1, z2 := mustHashValue(tc.zero)
+			if h1 != z1 || h2 != z2 {
+				t.Errorf("-0 and 0 have different hashes")
+			}
+
+			f := NewBloomFilter(100, 0.01)
+			f.Add(tc.zero)
+			c := NewCuckooFilter(16, 16)
+			if err := c.Add(tc.zero); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !f.Contains(tc.val) || !c.Contains(tc.val) {
+				t.Errorf("-0 is not found after adding 0")
+			}
+		})
+	}
+}
+
+func TestBloomFilter_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, 1, -0.5, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for rate %v", rate)
+				}
+			}()
+			NewBloomFilter(10, rate)
+		}()
+	}
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	a := NewBloomFilter(1000, 0.01)
+	b := NewBloomFilter(1000, 0.01)
+	a.Append(1, 2, 3)
+	b.Append(4, 5)
+
+	u, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, val := range []interface{}{1, 2, 3, 4, 5} {
+		if !u.Contains(val) {
+			t.Errorf("%v is not found in the union", val)
+		}
+	}
+	if a.Contains(4) || b.Contains(1) {
+		t.Errorf("Union modified the filters")
+	}
+
+	if _, err := a.Union(NewBloomFilter(1000, 0.1)); !errors.Is(err, ErrIncompatibleFilter) {
+		t.Errorf("expected ErrIncompatibleFilter, actual %v", err)
+	}
+}
+
+func TestBloomFilter_MarshalBinary(t *testing.T) {
+	f := NewBloomFilter(500, 0.01)
+	for i := 0; i < 500; i++ {
+		f.Add(i)
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g := NewBloomFilter(1, 0.5)
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 500; i++ {
+		if !g.Contains(i) {
+			t.Fatalf("%v is not found after UnmarshalBinary", i)
+		}
+	}
+	if _, err := g.Union(f); err != nil {
+		t.Errorf("decoded filter is not compatible: %v", err)
+	}
+
+	invalid := map[string][]byte{
+		"Empty data":      nil,
+		"Wrong header":    append([]byte("XXXX"), data[4:]...),
+		"Counting header": append(append([]byte(nil), countingBloomMagic[:]...), data[4:]...),
+		"Truncated words": data[:len(data)-1],
+		"Zero hashes":     append(append(append([]byte(nil), data[:12]...), 0, 0, 0, 0), data[16:]...),
+	}
+	for name, data := range invalid {
+		if err := g.UnmarshalBinary(data); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("%s: expected ErrInvalidFormat, actual %v", name, err)
+		}
+	}
+}
+
+func TestFilter_UnmarshalBinaryLargeSize(t *testing.T) {
+	testCases := []struct {
+		name  string
+		magic [4]byte
+		m     uint64
+		size  int
+	}{
+		{name: "Bloom max bits", magic: bloomMagic, m: math.MaxUint64, size: 8},
+		{name: "Bloom wrapping bits", magic: bloomMagic, m: math.MaxUint64 - wordSize + 2, size: 0},
+		{name: "Bloom too many bits", magic: bloomMagic, m: 1 << 40, size: 64},
+		{name: "Counting max counters", magic: countingBloomMagic, m: math.MaxUint64, size: 8},
+		{name: "Counting too many counters", magic: countingBloomMagic, m: 1 << 40, size: 64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append(appendFilterHeader(nil, tc.magic, tc.m, 3), make([]byte, tc.size)...)
+			var err error
+			if tc.magic == bloomMagic {
+				err = new(BloomFilter).UnmarshalBinary(data)
+			} else {
+				err = new(CountingBloomFilter).UnmarshalBinary(data)
+			}
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("expected ErrInvalidFormat, actual %v", err)
+			}
+		})
+	}
+}
+
+func FuzzBloomFilter_UnmarshalBinary(f *testing.F) {
+	b := NewBloomFilter(10, 0.1)
+	b.Add(1)
+	data, _ := b.MarshalBinary()
+	f.Add(data)
+	f.Add(appendFilterHeader(nil, bloomMagic, math.MaxUint64, 1))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var g BloomFilter
+		if err := g.UnmarshalBinary(data); err != nil {
+			return
+		}
+		out, err := g.MarshalBinary()
+		if err != nil || string(out) != string(data) {
+			t.Errorf("decoded filter is encoded differently: %v", err)
+		}
+	})
+}
+
+func TestCountingBloomFilter_Remove(t *testing.T) {
+	f := NewCountingBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.Add(i)
+	}
+	for i := 0; i < 1000; i += 2 {
+		f.Remove(i)
+	}
+
+	var found int
+	for i := 0; i < 1000; i++ {
+		if i%2 == 1 && !f.Contains(i) {
+			t.Fatalf("%v is not found", i)
+		}
+		if i%2 == 0 && f.Contains(i) {
+			found++
+		}
+	}
+	if found > 50 {
+		t.Errorf("%v removed values are still found", found)
+	}
+	if est := f.EstimatedSize(); est < 450 || est > 550 {
+		t.Errorf("expected estimated size around 500, actual %v", est)
+	}
+
+	for i := 1; i < 1000; i += 2 {
+		f.Remove(i)
+	}
+	if !f.Empty() {
+		t.Errorf("filter is not empty after removing all values")
+	}
+}
+
+func TestCountingBloomFilter_Saturation(t *testing.T) {
+	f := NewCountingBloomFilter(10, 0.1)
+	for i := 0; i < 300; i++ {
+		f.Add("x")
+	}
+	for i := 0; i < 300; i++ {
+		f.Remove("x")
+	}
+	if !f.Contains("x") {
+		t.Errorf("saturated value is removed")
+	}
+}
+
+func TestCountingBloomFilter_UnionMarshal(t *testing.T) {
+	a := NewCountingBloomFilter(100, 0.01)
+	b := NewCountingBloomFilter(100, 0.01)
+	a.Append("a", "b")
+	b.Append("b", "c")
+
+	u, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u.Remove("b")
+	for _, val := range []interface{}{"a", "b", "c"} {
+		if !u.Contains(val) {
+			t.Errorf("%v is not found in the union", val)
+		}
+	}
+	if _, err := a.Union(NewCountingBloomFilter(10, 0.01)); !errors.Is(err, ErrIncompatibleFilter) {
+		t.Errorf("expected ErrIncompatibleFilter, actual %v", err)
+	}
+
+	data, err := u.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g := NewCountingBloomFilter(1, 0.5)
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, val := range []interface{}{"a", "b", "c"} {
+		if !g.Contains(val) {
+			t.Errorf("%v is not found after UnmarshalBinary", val)
+		}
+	}
+	if err := g.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, actual %v", err)
+	}
+	if err := new(BloomFilter).UnmarshalBinary(data); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat for BloomFilter, actual %v", err)
+	}
+}
+
+var _ Membership = (*BloomFilter)(nil)
+var _ Membership = (*CountingBloomFilter)(nil)
@@ -0,0 +1,48 @@
+package set
+
+import "errors"
+
+// ErrCapacityExceeded is returned when a value cannot be added into a bounded
+// set because the set is full and its policy is EvictReject.
+var ErrCapacityExceeded = errors.New("set: capacity exceeded")
+
+// BoundedSet is a Set which keeps at most a fixed number of values. When a new
+// value is added into a full set, the eviction policy of the set decides
+// whether a value is evicted for it or the new value is rejected. TryAdd
+// returns the rejections as an error, while Add and Append ignore them.
+//
+// Slice, Each and All visit the values from the next evicted one to the last
+// evicted one for EvictLRU and EvictFIFO, and in the insertion order for
+// EvictReject. Pop removes the value which would be evicted next, or the first
+// added value for EvictReject.
+//
+// The sets returned from the binary operations and the functional methods are
+// new bounded sets with the same kind, capacity and policy, but without the
+// eviction callback. They may evict values if the result does not fit into the
+// capacity.
+type BoundedSet interface {
+	Set
+	TryAdd(val interface{}) error
+	Capacity() uint
+}
+
+// NewBounded creates a bounded set data structure regarding setType. Only
+// ThreadSafe and ThreadUnsafe are supported. The set keeps at most maxSize
+// values and policy decides what happens when it is full. onEvict is called
+// with every evicted value, and it can be nil. maxSize must be positive.
+//
+//	lru := set.NewBounded(set.ThreadSafe, 1000, set.EvictLRU, nil)
+//	strict := set.NewBounded(set.ThreadUnsafe, 10, set.EvictReject, nil)
+func NewBounded(t setType, maxSize uint, policy EvictionPolicy, onEvict func(val interface{})) BoundedSet {
+	if maxSize == 0 {
+		panic("set: capacity of a bounded set must be positive")
+	}
+	var set BoundedSet
+	switch t {
+	case ThreadSafe:
+		set = &ThreadSafeBoundedSet{set: newThreadUnsafeBoundedSet(maxSize, policy, nil), onEvict: onEvict}
+	case ThreadUnsafe:
+		set = newThreadUnsafeBoundedSet(maxSize, policy, onEvict)
+	}
+	return set
+}
@@ -0,0 +1,270 @@
+package set
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var boundedSetTypes = []struct {
+	name string
+	t    setType
+}{
+	{name: "ThreadSafe", t: ThreadSafe},
+	{name: "ThreadUnsafe", t: ThreadUnsafe},
+}
+
+func TestBoundedSet_Policies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     EvictionPolicy
+		ops        func(s BoundedSet)
+		expSlice   []interface{}
+		expEvicted []interface{}
+	}{
+		{
+			name:   "Reject",
+			policy: EvictReject,
+			ops: func(s BoundedSet) {
+				s.Append(1, 2, 3, 4)
+				s.Contains(1)
+			},
+			expSlice: []interface{}{1, 2, 3},
+		},
+		{
+			name:   "LRU",
+			policy: EvictLRU,
+			ops: func(s BoundedSet) {
+				s.Append(1, 2, 3)
+				s.Contains(1)
+				s.Add(2)
+				s.Add(4)
+				s.Add(5)
+			},
+			expSlice:   []interface{}{2, 4, 5},
+			expEvicted: []interface{}{3, 1},
+		},
+		{
+			name:   "LFU",
+			policy: EvictLFU,
+			ops: func(s BoundedSet) {
+				s.Append(1, 2, 3)
+				s.Contains(1)
+				s.Contains(1)
+				s.Contains(3)
+				s.Add(4) // Evicts 2 which is used once.
+				s.Add(5) // Evicts 4 which is used once.
+				s.Contains(5)
+				s.Contains(5)
+				s.Add(6) // Evicts 3 which is used twice but earlier than 5.
+			},
+			expEvicted: []interface{}{2, 4, 3},
+		},
+		{
+			name:   "FIFO",
+			policy: EvictFIFO,
+			ops: func(s BoundedSet) {
+				s.Append(1, 2, 3)
+				s.Contains(1)
+				s.Add(1)
+				s.Add(4)
+			},
+			expSlice:   []interface{}{2, 3, 4},
+			expEvicted: []interface{}{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, st := range boundedSetTypes {
+			t.Run(tc.name+"/"+st.name, func(t *testing.T) {
+				var evicted []interface{}
+				s := NewBounded(st.t, 3, tc.policy, func(val interface{}) {
+					evicted = append(evicted, val)
+				})
+				tc.ops(s)
+
+				if !reflect.DeepEqual(evicted, tc.expEvicted) {
+					t.Errorf("expected evicted %v, actual %v", tc.expEvicted, evicted)
+				}
+				if tc.expSlice != nil && !reflect.DeepEqual(s.Slice(), tc.expSlice) {
+					t.Errorf("expected %v, actual %v", tc.expSlice, s.Slice())
+				}
+				if s.Size() != 3 || s.Capacity() != 3 {
+					t.Errorf("expected size and capacity 3, actual %v and %v", s.Size(), s.Capacity())
+				}
+			})
+		}
+	}
+}
+
+func TestBoundedSet_TryAdd(t *testing.T) {
+	for _, st := range boundedSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			s := NewBounded(st.t, 2, EvictReject, nil)
+			for _, val := range []interface{}{1, 2, 2} {
+				if err := s.TryAdd(val); err != nil {
+					t.Errorf("unexpected error for %v: %v", val, err)
+				}
+			}
+			if err := s.TryAdd(3); !errors.Is(err, ErrCapacityExceeded) {
+				t.Errorf("expected ErrCapacityExceeded, actual %v", err)
+			}
+			s.Remove(1)
+			if err := s.TryAdd(3); err != nil {
+				t.Errorf("unexpected error after Remove: %v", err)
+			}
+
+			lru := NewBounded(st.t, 1, EvictLRU, nil)
+			lru.Add(1)
+			if err := lru.TryAdd(2); err != nil || lru.Contains(1) {
+				t.Errorf("expected 1 to be evicted, err %v", err)
+			}
+		})
+	}
+}
+
+func TestBoundedSet_Random(t *testing.T) {
+	for _, st := range boundedSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			evicted := map[interface{}]bool{}
+			s := NewBounded(st.t, 10, EvictRandom, func(val interface{}) {
+				evicted[val] = true
+			})
+			for i := 0; i < 100; i++ {
+				s.Add(i)
+			}
+			if s.Size() != 10 || len(evicted) != 90 {
+				t.Errorf("expected 10 values and 90 evictions, actual %v and %v", s.Size(), len(evicted))
+			}
+			s.Each(func(val interface{}) bool {
+				if evicted[val] {
+					t.Errorf("evicted value %v is in the set", val)
+				}
+				return true
+			})
+			for s.Size() > 0 {
+				peeked := s.Peek()
+				if s.Peek() != peeked {
+					t.Fatalf("Peek returned different values")
+				}
+				if val := s.Pop(); val != peeked {
+					t.Fatalf("expected to pop %v, actual %v", peeked, val)
+				}
+			}
+		})
+	}
+}
+
+func TestBoundedSet_RandomPeekAdd(t *testing.T) {
+	for _, st := range boundedSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			var evicted interface{}
+			s := NewBounded(st.t, 10, EvictRandom, func(val interface{}) {
+				evicted = val
+			})
+			for i := 0; i < 10; i++ {
+				s.Add(i)
+			}
+			for i := 10; i < 50; i++ {
+				peeked := s.Peek()
+				s.Add(i)
+				if evicted != peeked {
+					t.Fatalf("expected %v to be evicted, actual %v", peeked, evicted)
+				}
+			}
+		})
+	}
+}
+
+func TestBoundedSet_PopAndClear(t *testing.T) {
+	for _, st := range boundedSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			s := NewBounded(st.t, 5, EvictLRU, nil)
+			s.Append(1, 2, 3)
+			s.Contains(1)
+			if s.Peek() != 2 {
+				t.Errorf("expected 2 as the next evicted value, actual %v", s.Peek())
+			}
+			if val := s.Pop(); val != 2 {
+				t.Errorf("expected to pop 2, actual %v", val)
+			}
+			s.Clear()
+			if !s.Empty() || s.Peek() != nil {
+				t.Errorf("set is not empty after Clear")
+			}
+			if _, ok := s.TryPop(); ok {
+				t.Errorf("TryPop returned true for an empty set")
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for zero capacity")
+		}
+	}()
+	NewBounded(ThreadUnsafe, 0, EvictLRU, nil)
+}
+
+func TestBoundedSet_Operations(t *testing.T) {
+	for _, st := range boundedSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			s := NewBounded(st.t, 3, EvictFIFO, nil)
+			s.Append(1, 2, 3)
+			other := NewOf(ThreadUnsafe, 2, 3, 4)
+
+			union := s.Union(other)
+			if _, ok := union.(BoundedSet); !ok {
+				t.Fatalf("Union returned %T", union)
+			}
+			// The union does not fit into the capacity, so 1 is evicted.
+			checkSameSet(t, "Union", union, NewOf(ThreadUnsafe, 2, 3, 4))
+			checkSameSet(t, "Intersection", s.Intersection(other), NewOf(ThreadUnsafe, 2, 3))
+			checkSameSet(t, "Difference", s.Difference(other), NewOf(ThreadUnsafe, 1))
+			checkSameSet(t, "SymmetricDifference", s.SymmetricDifference(other), NewOf(ThreadUnsafe, 1, 4))
+			checkSameSet(t, "Filter", s.Filter(isEven), NewOf(ThreadUnsafe, 2))
+			in, out := s.Partition(isEven)
+			checkSameSet(t, "Partition in", in, NewOf(ThreadUnsafe, 2))
+			checkSameSet(t, "Partition out", out, NewOf(ThreadUnsafe, 1, 3))
+			checkSameSet(t, "Map", s.Map(func(val interface{}) interface{} { return val.(int) * 10 }),
+				NewOf(ThreadUnsafe, 10, 20, 30))
+			if s.IsSubset(other) || s.IsSuperset(other) || s.IsDisjoint(other) || s.Equal(other) {
+				t.Errorf("unexpected comparison result")
+			}
+			if !s.Equal(s) || !s.IsSuperset(s) || s.CountIf(isEven) != 1 || !s.AnyMatch(isEven) || s.AllMatch(isEven) {
+				t.Errorf("unexpected comparison result with itself")
+			}
+			if s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) }) != 6 {
+				t.Errorf("unexpected Reduce result")
+			}
+		})
+	}
+}
+
+func TestThreadSafeBoundedSet_Concurrent(t *testing.T) {
+	var mu sync.Mutex
+	var evictions int
+	var s BoundedSet
+	s = NewBounded(ThreadSafe, 100, EvictLFU, func(val interface{}) {
+		// The callback runs without the lock, so it can use the set.
+		s.Size()
+		mu.Lock()
+		evictions++
+		mu.Unlock()
+	})
+	runStress(t, 1000,
+		func(i int) { s.Add(i) },
+		func(i int) { s.Append(-i, i+1000) },
+		func(i int) { s.Contains(i) },
+		func(i int) { s.Union(s) },
+		func(i int) { s.Remove(i - 1) },
+		func(i int) { s.Slice() },
+	)
+	if s.Size() > 100 {
+		t.Errorf("expected at most 100 values, actual %v", s.Size())
+	}
+	if evictions == 0 {
+		t.Errorf("expected evictions")
+	}
+}
@@ -0,0 +1,254 @@
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// ThreadSafeBoundedSet is a bounded set type which provides the thread-safety.
+// Contains counts as a use of the value for EvictLRU and EvictLFU, so it takes
+// the write lock. The eviction callback is called after the lock is released,
+// so it can use the set.
+type ThreadSafeBoundedSet struct {
+	set     *ThreadUnsafeBoundedSet
+	rw      sync.RWMutex
+	onEvict func(val interface{})
+}
+
+// wrapBounded returns a new *ThreadSafeBoundedSet for the result of an operation of
+// the underlying set.
+func wrapBounded(set Set) *ThreadSafeBoundedSet {
+	return &ThreadSafeBoundedSet{set: set.(*ThreadUnsafeBoundedSet)}
+}
+
+// empty returns a new empty set with the same capacity and policy.
+func (s *ThreadSafeBoundedSet) empty() *ThreadSafeBoundedSet {
+	return &ThreadSafeBoundedSet{set: s.set.empty()}
+}
+
+// notify calls the eviction callback for the evicted entries.
+func (s *ThreadSafeBoundedSet) notify(evicted []*boundedEntry) {
+	if s.onEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		s.onEvict(e.val)
+	}
+}
+
+// TryAdd adds a new value to set. If the set is full, it evicts a value by the
+// policy or returns ErrCapacityExceeded for EvictReject. Adding an existing
+// value counts as a use.
+func (s *ThreadSafeBoundedSet) TryAdd(val interface{}) error {
+	s.rw.Lock()
+	evicted, err := s.set.tryAdd(val)
+	s.rw.Unlock()
+	if evicted != nil {
+		s.notify([]*boundedEntry{evicted})
+	}
+	return err
+}
+
+// Add adds a new value to set like TryAdd, but it ignores the rejection.
+func (s *ThreadSafeBoundedSet) Add(val interface{}) {
+	_ = s.TryAdd(val)
+}
+
+// Append adds multiple values into set in order. The rejected values are
+// ignored.
+func (s *ThreadSafeBoundedSet) Append(values ...interface{}) {
+	var evicted []*boundedEntry
+	s.rw.Lock()
+	for _, val := range values {
+		if e, _ := s.set.tryAdd(val); e != nil {
+			evicted = append(evicted, e)
+		}
+	}
+	s.rw.Unlock()
+	s.notify(evicted)
+}
+
+// Capacity returns the maximum number of the values in the set.
+func (s *ThreadSafeBoundedSet) Capacity() uint {
+	// The capacity never changes, so it does not need the lock.
+	return s.set.Capacity()
+}
+
+// Remove deletes the given value. The eviction callback is not called for it.
+func (s *ThreadSafeBoundedSet) Remove(val interface{}) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.set.Remove(val)
+}
+
+// Contains checks the value whether exists in the set. It counts as a use of
+// the value for EvictLRU and EvictLFU.
+func (s *ThreadSafeBoundedSet) Contains(val interface{}) bool {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	return s.set.Contains(val)
+}
+
+// Size returns the length of the set.
+func (s *ThreadSafeBoundedSet) Size() uint {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.set.Size()
+}
+
+// Pop removes the value which would be evicted next and returns it. If there is
+// no element in set, it returns nil.
+func (s *ThreadSafeBoundedSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the value which would be evicted next and returns it with
+// true. If there is no element in set, it returns nil and false.
+func (s *ThreadSafeBoundedSet) TryPop() (interface{}, bool) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	return s.set.TryPop()
+}
+
+// Peek returns the value which would be evicted next without removing it. If
+// there is no element in set, it returns nil. It takes the write lock, because
+// EvictRandom keeps the chosen value for the next Pop.
+func (s *ThreadSafeBoundedSet) Peek() interface{} {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	return s.set.Peek()
+}
+
+// Clear removes everything from the set. The eviction callback is not called
+// for the removed values.
+func (s *ThreadSafeBoundedSet) Clear() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.set.Clear()
+}
+
+// Empty checks whether the set is empty.
+func (s *ThreadSafeBoundedSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Slice returns the values of the set as a slice in the order of the policy.
+func (s *ThreadSafeBoundedSet) Slice() []interface{} {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.set.Slice()
+}
+
+// Each calls fn for every value in the set in the order of the policy until fn
+// returns false. The read lock is held during the iteration, so the set must
+// not be modified from fn.
+func (s *ThreadSafeBoundedSet) Each(fn func(val interface{}) bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	s.set.Each(fn)
+}
+
+// All returns an iterator over the values of the set. It follows the same rules
+// with Each, so the set must not be modified from the loop body.
+func (s *ThreadSafeBoundedSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred. pred
+// is called under the read lock, so it must not modify the set.
+func (s *ThreadSafeBoundedSet) Filter(pred func(val interface{}) bool) Set {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return wrapBounded(s.set.Filter(pred))
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. fn is called under the read lock, so it must not modify the set.
+func (s *ThreadSafeBoundedSet) Map(fn func(val interface{}) interface{}) Set {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return wrapBounded(s.set.Map(fn))
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. fn is called under the read lock, so it
+// must not modify the set.
+func (s *ThreadSafeBoundedSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the values
+// which satisfy pred and the second one contains the rest. pred is called under
+// the read lock, so it must not modify the set.
+func (s *ThreadSafeBoundedSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	in, out := s.set.Partition(pred)
+	return wrapBounded(in), wrapBounded(out)
+}
+
+// AnyMatch returns true if at least one value satisfies pred. pred is called
+// under the read lock, so it must not modify the set.
+func (s *ThreadSafeBoundedSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values satisfy pred. It returns true for an
+// empty set. pred is called under the read lock, so it must not modify the
+// set.
+func (s *ThreadSafeBoundedSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of the values which satisfy pred. pred is called
+// under the read lock, so it must not modify the set.
+func (s *ThreadSafeBoundedSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *ThreadSafeBoundedSet that contains the items of the
+// receiver set followed by the items of the given set.
+func (s *ThreadSafeBoundedSet) Union(set Set) Set {
+	return union(s.empty(), s, set)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *ThreadSafeBoundedSet that stores the common ones.
+func (s *ThreadSafeBoundedSet) Intersection(set Set) Set {
+	return intersection(s.empty(), s, set)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *ThreadSafeBoundedSet.
+func (s *ThreadSafeBoundedSet) Difference(set Set) Set {
+	return difference(s.empty(), s, set)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false.
+func (s *ThreadSafeBoundedSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It does not count as a use of the values.
+func (s *ThreadSafeBoundedSet) IsSuperset(set Set) bool {
+	return isSubset(set, s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets.
+func (s *ThreadSafeBoundedSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values.
+func (s *ThreadSafeBoundedSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *ThreadSafeBoundedSet that contains the
+// items which only exist in one of the sets.
+func (s *ThreadSafeBoundedSet) SymmetricDifference(set Set) Set {
+	return symmetricDifference(s.empty(), s, set)
+}
@@ -0,0 +1,365 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+)
+
+// ThreadUnsafeBoundedSet is a bounded set type which does not provide the
+// thread-safety.
+type ThreadUnsafeBoundedSet struct {
+	set     map[interface{}]*boundedEntry
+	ev      evictor
+	max     uint
+	policy  EvictionPolicy
+	onEvict func(val interface{})
+}
+
+// newThreadUnsafeBoundedSet creates a new *ThreadUnsafeBoundedSet.
+func newThreadUnsafeBoundedSet(maxSize uint, policy EvictionPolicy, onEvict func(val interface{})) *ThreadUnsafeBoundedSet {
+	return &ThreadUnsafeBoundedSet{
+		set:     make(map[interface{}]*boundedEntry),
+		ev:      newEvictor(policy),
+		max:     maxSize,
+		policy:  policy,
+		onEvict: onEvict,
+	}
+}
+
+// empty returns a new empty set with the same capacity and policy.
+func (s *ThreadUnsafeBoundedSet) empty() *ThreadUnsafeBoundedSet {
+	return newThreadUnsafeBoundedSet(s.max, s.policy, nil)
+}
+
+// tryAdd adds val and returns the evicted entry if there is one. It does not
+// call the eviction callback.
+func (s *ThreadUnsafeBoundedSet) tryAdd(val interface{}) (*boundedEntry, error) {
+	if e, ok := s.set[val]; ok {
+		s.ev.access(e)
+		return nil, nil
+	}
+	var evicted *boundedEntry
+	if uint(len(s.set)) >= s.max {
+		if s.policy == EvictReject {
+			return nil, fmt.Errorf("%w: %d values", ErrCapacityExceeded, s.max)
+		}
+		evicted = s.ev.victim()
+		s.removeEntry(evicted)
+	}
+	e := &boundedEntry{val: val}
+	s.set[val] = e
+	s.ev.add(e)
+	return evicted, nil
+}
+
+// removeEntry deletes the entry from the set.
+func (s *ThreadUnsafeBoundedSet) removeEntry(e *boundedEntry) {
+	delete(s.set, e.val)
+	s.ev.remove(e)
+}
+
+// TryAdd adds a new value to set. If the set is full, it evicts a value by the
+// policy or returns ErrCapacityExceeded for EvictReject. Adding an existing
+// value counts as a use. It is not a thread-safe method.
+//
+// Example:
+//	err := s.TryAdd("str")
+func (s *ThreadUnsafeBoundedSet) TryAdd(val interface{}) error {
+	evicted, err := s.tryAdd(val)
+	if evicted != nil && s.onEvict != nil {
+		s.onEvict(evicted.val)
+	}
+	return err
+}
+
+// Add adds a new value to set like TryAdd, but it ignores the rejection. It is
+// not a thread-safe method.
+//
+// Example:
+//	s.Add("str")
+func (s *ThreadUnsafeBoundedSet) Add(val interface{}) {
+	_ = s.TryAdd(val)
+}
+
+// Append adds multiple values into set in order. The rejected values are
+// ignored. It is not a thread-safe method.
+//
+// Example:
+//	s.Append(1, 2, 3)
+func (s *ThreadUnsafeBoundedSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Capacity returns the maximum number of the values in the set.
+//
+// Example:
+//	capacity := s.Capacity()
+func (s *ThreadUnsafeBoundedSet) Capacity() uint {
+	return s.max
+}
+
+// Remove deletes the given value. The eviction callback is not called for it.
+// It is not a thread-safe method.
+//
+// Example:
+//	s.Remove("str")
+func (s *ThreadUnsafeBoundedSet) Remove(val interface{}) {
+	if e, ok := s.set[val]; ok {
+		s.removeEntry(e)
+	}
+}
+
+// Contains checks the value whether exists in the set. It counts as a use of
+// the value for EvictLRU and EvictLFU. It is not a thread-safe method.
+//
+// Example:
+//	exist := s.Contains("str")
+func (s *ThreadUnsafeBoundedSet) Contains(val interface{}) bool {
+	e, ok := s.set[val]
+	if ok {
+		s.ev.access(e)
+	}
+	return ok
+}
+
+// Size returns the length of the set. It is not a thread-safe method.
+//
+// Example:
+//	size := s.Size()
+func (s *ThreadUnsafeBoundedSet) Size() uint {
+	return uint(len(s.set))
+}
+
+// Pop removes the value which would be evicted next and returns it. If there is
+// no element in set, it returns nil. It is not a thread-safe method.
+//
+// Example:
+//	val := s.Pop()
+func (s *ThreadUnsafeBoundedSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the value which would be evicted next and returns it with
+// true. If there is no element in set, it returns nil and false. It is not a
+// thread-safe method.
+//
+// Example:
+//	val, ok := s.TryPop()
+func (s *ThreadUnsafeBoundedSet) TryPop() (interface{}, bool) {
+	e := s.ev.victim()
+	if e == nil {
+		return nil, false
+	}
+	s.removeEntry(e)
+	return e.val, true
+}
+
+// Peek returns the value which would be evicted next without removing it. If
+// there is no element in set, it returns nil. It is not a thread-safe method.
+//
+// Example:
+//	val := s.Peek()
+func (s *ThreadUnsafeBoundedSet) Peek() interface{} {
+	if e := s.ev.victim(); e != nil {
+		return e.val
+	}
+	return nil
+}
+
+// Clear removes everything from the set. The eviction callback is not called
+// for the removed values. It is not a thread-safe method.
+//
+// Example:
+//	s.Clear()
+func (s *ThreadUnsafeBoundedSet) Clear() {
+	s.set = make(map[interface{}]*boundedEntry)
+	s.ev.clear()
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method.
+//
+// Example:
+//	empty := s.Empty()
+func (s *ThreadUnsafeBoundedSet) Empty() bool {
+	return len(s.set) == 0
+}
+
+// Slice returns the values of the set as a slice in the order of the policy.
+// It is not a thread-safe method.
+//
+// Example:
+//	values := s.Slice()
+func (s *ThreadUnsafeBoundedSet) Slice() []interface{} {
+	values := make([]interface{}, 0, len(s.set))
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every value in the set in the order of the policy until fn
+// returns false. It does not count as a use of the values. fn may remove the
+// value which is given to it. It is not a thread-safe method.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *ThreadUnsafeBoundedSet) Each(fn func(val interface{}) bool) {
+	s.ev.each(func(e *boundedEntry) bool {
+		return fn(e.val)
+	})
+}
+
+// All returns an iterator over the values of the set. It follows the same
+// rules with Each. It is not a thread-safe method.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ThreadUnsafeBoundedSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new bounded set that contains the values which satisfy
+// pred. It is not a thread-safe method.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeBoundedSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, s.empty(), pred)
+}
+
+// Map returns a new bounded set that contains the results of fn for every
+// value in the set. It is not a thread-safe method.
+//
+// Example:
+//	doubled := s.Map(func(val interface{}) interface{} { return val.(int) * 2 })
+func (s *ThreadUnsafeBoundedSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, s.empty(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. It is not a thread-safe method.
+//
+// Example:
+//	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+func (s *ThreadUnsafeBoundedSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new bounded sets. The first one contains
+// the values which satisfy pred and the second one contains the rest. It is
+// not a thread-safe method.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeBoundedSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, s.empty(), s.empty(), pred)
+}
+
+// AnyMatch returns true if at least one value satisfies pred. It is not a
+// thread-safe method.
+//
+// Example:
+//	hasEven := s.AnyMatch(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeBoundedSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values satisfy pred. It returns true for an
+// empty set. It is not a thread-safe method.
+//
+// Example:
+//	allEven := s.AllMatch(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeBoundedSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of the values which satisfy pred. It is not a
+// thread-safe method.
+//
+// Example:
+//	evens := s.CountIf(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeBoundedSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new bounded set that contains the items of the receiver set
+// followed by the items of the given set. It is not a thread-safe method.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *ThreadUnsafeBoundedSet) Union(set Set) Set {
+	return union(s.empty(), s, set)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// bounded set that stores them. It is not a thread-safe method.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *ThreadUnsafeBoundedSet) Intersection(set Set) Set {
+	return intersection(s.empty(), s, set)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new bounded set. It is not a thread-safe method.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *ThreadUnsafeBoundedSet) Difference(set Set) Set {
+	return difference(s.empty(), s, set)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *ThreadUnsafeBoundedSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It does not count as a use of the values. It is
+// not a thread-safe method.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *ThreadUnsafeBoundedSet) IsSuperset(set Set) bool {
+	return isSubset(set, s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *ThreadUnsafeBoundedSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values. It is not a
+// thread-safe method.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *ThreadUnsafeBoundedSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new bounded set that contains the items which
+// only exist in one of the sets. It is not a thread-safe method.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *ThreadUnsafeBoundedSet) SymmetricDifference(set Set) Set {
+	return symmetricDifference(s.empty(), s, set)
+}
@@ -0,0 +1,190 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Comparator compares two values. It returns a negative number if a is less
+// than b, zero if they are equal and a positive number if a is greater than b.
+// A Comparator must define a total order and it must return zero only for the
+// equal values, otherwise the ordered sets may lose the values.
+type Comparator func(a, b interface{}) int
+
+// Type groups of the values for Compare. The values are sorted by group first.
+const (
+	groupNil = iota
+	groupBool
+	groupNumber
+	groupString
+	groupOther
+)
+
+// Compare is the default Comparator. It can compare the values of the mixed
+// types. The values are grouped by their types in the order of nil, bools,
+// numbers, strings and the other types, then sorted in the natural order in the
+// group. The numbers of different types are compared by their values and the
+// equal numbers, such as int(1) and int64(1), are sorted by their type names.
+// NaN is less than all other numbers. The other types are sorted by their type
+// names and then by their Go syntax representation.
+func Compare(a, b interface{}) int {
+	ga, gb := compareGroup(a), compareGroup(b)
+	if ga != gb {
+		return compareInts(int64(ga), int64(gb))
+	}
+
+	switch ga {
+	case groupNil:
+		return 0
+	case groupBool:
+		x, y := a.(bool), b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		default:
+			return 1
+		}
+	case groupNumber:
+		if c := compareNumbers(reflect.ValueOf(a), reflect.ValueOf(b)); c != 0 {
+			return c
+		}
+	case groupString:
+		x, y := reflect.ValueOf(a).String(), reflect.ValueOf(b).String()
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	default:
+		if a == b {
+			return 0
+		}
+	}
+
+	if c := compareStrings(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b)); c != 0 || ga != groupOther {
+		return c
+	}
+	switch av := reflect.ValueOf(a); av.Kind() {
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		// The distinct pointers may point to the same value, so they are
+		// compared by their addresses.
+		return compareUints(uint64(av.Pointer()), uint64(reflect.ValueOf(b).Pointer()))
+	}
+	return compareStrings(fmt.Sprintf("%#v", a), fmt.Sprintf("%#v", b))
+}
+
+// compareGroup returns the type group of val.
+func compareGroup(val interface{}) int {
+	if val == nil {
+		return groupNil
+	}
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Bool:
+		if _, ok := val.(bool); ok {
+			return groupBool
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return groupNumber
+	case reflect.String:
+		return groupString
+	}
+	return groupOther
+}
+
+// compareNumbers compares two numbers of any integer or float kind by their
+// values.
+func compareNumbers(a, b reflect.Value) int {
+	af, bf := isFloat(a), isFloat(b)
+	if af || bf {
+		x, y := toFloat(a), toFloat(b)
+		switch {
+		case math.IsNaN(x) && math.IsNaN(y):
+			return 0
+		case math.IsNaN(x):
+			return -1
+		case math.IsNaN(y):
+			return 1
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	as, bs := isSigned(a), isSigned(b)
+	switch {
+	case as && bs:
+		return compareInts(a.Int(), b.Int())
+	case !as && !bs:
+		return compareUints(a.Uint(), b.Uint())
+	case as:
+		if a.Int() < 0 {
+			return -1
+		}
+		return compareUints(uint64(a.Int()), b.Uint())
+	default:
+		if b.Int() < 0 {
+			return 1
+		}
+		return compareUints(a.Uint(), uint64(b.Int()))
+	}
+}
+
+func isFloat(v reflect.Value) bool {
+	return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func isSigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isFloat(v):
+		return v.Float()
+	case isSigned(v):
+		return float64(v.Int())
+	default:
+		return float64(v.Uint())
+	}
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
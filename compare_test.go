@@ -0,0 +1,61 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	type point struct{ x, y int }
+	p1, p2 := &point{1, 2}, &point{1, 2}
+
+	testCases := []struct {
+		name string
+		a, b interface{}
+		exp  int
+	}{
+		{"Nil and nil", nil, nil, 0},
+		{"Nil and bool", nil, false, -1},
+		{"False and true", false, true, -1},
+		{"Bool and number", true, 0, -1},
+		{"Ints", 1, 2, -1},
+		{"Equal ints", 2, 2, 0},
+		{"Int and float", 2, 1.5, 1},
+		{"Negative int and uint", -1, uint(0), -1},
+		{"Int and uint", 5, uint(3), 1},
+		{"Equal numbers of different types", 1, int64(1), -1},
+		{"NaN and number", math.NaN(), -1000, -1},
+		{"Number and string", 100, "a", -1},
+		{"Strings", "abc", "abd", -1},
+		{"String and struct", "z", point{}, -1},
+		{"Structs", point{1, 2}, point{1, 3}, -1},
+		{"Equal structs", point{1, 2}, point{1, 2}, 0},
+		{"Same pointer", p1, p1, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Compare(tc.a, tc.b)
+			if sign(c) != tc.exp {
+				t.Errorf("expected %v, actual %v", tc.exp, c)
+			}
+			if sign(Compare(tc.b, tc.a)) != -tc.exp {
+				t.Errorf("expected %v for reversed order, actual %v", -tc.exp, -c)
+			}
+		})
+	}
+
+	if Compare(p1, p2) == 0 {
+		t.Errorf("expected different pointers are not equal")
+	}
+}
+
+func sign(c int) int {
+	switch {
+	case c < 0:
+		return -1
+	case c > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,341 @@
+package set
+
+import (
+	"iter"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWriteSet is a thread-safe set type which is optimized for the rare
+// writes and the frequent reads. The values are kept in an immutable snapshot
+// which is swapped atomically, so the reads such as Contains, Size and Each do
+// not take any lock. Every write copies the snapshot, so the writes take O(n)
+// time; Append adds many values with a single copy. The writers are serialized
+// by a mutex.
+//
+// Each, All and the functional methods walk the snapshot which is current when
+// they start, so the set can be modified during the iteration and the changes
+// are not visited. The binary operations read a snapshot of both sets.
+type CopyOnWriteSet struct {
+	snap atomic.Pointer[map[interface{}]struct{}]
+	mu   sync.Mutex
+}
+
+// NewCopyOnWrite creates a new *CopyOnWriteSet. It is same with
+// New(CopyOnWrite), but it returns the concrete type.
+//
+//	s := set.NewCopyOnWrite()
+func NewCopyOnWrite() *CopyOnWriteSet {
+	return newCopyOnWriteOf(make(map[interface{}]struct{}))
+}
+
+// newCopyOnWriteOf creates a new *CopyOnWriteSet whose snapshot is m. m must
+// not be modified after that.
+func newCopyOnWriteOf(m map[interface{}]struct{}) *CopyOnWriteSet {
+	s := &CopyOnWriteSet{}
+	s.snap.Store(&m)
+	return s
+}
+
+// load returns the current snapshot. It must not be modified.
+func (s *CopyOnWriteSet) load() map[interface{}]struct{} {
+	return *s.snap.Load()
+}
+
+// update calls fn with the current snapshot under the writer lock. If fn
+// returns a non-nil map, it is published as the new snapshot.
+func (s *CopyOnWriteSet) update(fn func(m map[interface{}]struct{}) map[interface{}]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m := fn(s.load()); m != nil {
+		s.snap.Store(&m)
+	}
+}
+
+// Add adds a new value to set. It does not copy the snapshot if the value
+// already exists.
+func (s *CopyOnWriteSet) Add(val interface{}) {
+	s.Append(val)
+}
+
+// Append adds multiple values into set. The values are published together in
+// a single snapshot.
+func (s *CopyOnWriteSet) Append(values ...interface{}) {
+	s.update(func(m map[interface{}]struct{}) map[interface{}]struct{} {
+		var c map[interface{}]struct{}
+		for _, val := range values {
+			if _, ok := m[val]; ok {
+				continue
+			}
+			if c == nil {
+				c = maps.Clone(m)
+				m = c
+			}
+			c[val] = setVal
+		}
+		return c
+	})
+}
+
+// Remove deletes the given value. It does not copy the snapshot if the value
+// does not exist.
+func (s *CopyOnWriteSet) Remove(val interface{}) {
+	s.update(func(m map[interface{}]struct{}) map[interface{}]struct{} {
+		if _, ok := m[val]; !ok {
+			return nil
+		}
+		c := maps.Clone(m)
+		delete(c, val)
+		return c
+	})
+}
+
+// Contains checks the value whether exists in the set. It does not take any
+// lock.
+func (s *CopyOnWriteSet) Contains(val interface{}) bool {
+	_, ok := s.load()[val]
+	return ok
+}
+
+// Size returns the length of the set. It does not take any lock.
+func (s *CopyOnWriteSet) Size() uint {
+	return uint(len(s.load()))
+}
+
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns nil. Use TryPop for distinguishing a nil value
+// from the empty set.
+func (s *CopyOnWriteSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns nil and false.
+func (s *CopyOnWriteSet) TryPop() (interface{}, bool) {
+	var popped interface{}
+	var ok bool
+	s.update(func(m map[interface{}]struct{}) map[interface{}]struct{} {
+		for val := range m {
+			popped, ok = val, true
+			c := maps.Clone(m)
+			delete(c, val)
+			return c
+		}
+		return nil
+	})
+	return popped, ok
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns nil.
+func (s *CopyOnWriteSet) Peek() interface{} {
+	for val := range s.load() {
+		return val
+	}
+	return nil
+}
+
+// Clear removes everything from the set.
+func (s *CopyOnWriteSet) Clear() {
+	s.update(func(map[interface{}]struct{}) map[interface{}]struct{} {
+		return make(map[interface{}]struct{})
+	})
+}
+
+// Empty checks whether the set is empty.
+func (s *CopyOnWriteSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Slice returns the values of the current snapshot as a slice.
+func (s *CopyOnWriteSet) Slice() []interface{} {
+	m := s.load()
+	values := make([]interface{}, 0, len(m))
+	for val := range m {
+		values = append(values, val)
+	}
+	return values
+}
+
+// Each calls fn for every value in the current snapshot until fn returns
+// false. fn may modify the set, but the changes are not visited.
+func (s *CopyOnWriteSet) Each(fn func(val interface{}) bool) {
+	for val := range s.load() {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the current snapshot. It follows
+// the same rules with Each.
+func (s *CopyOnWriteSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred.
+func (s *CopyOnWriteSet) Filter(pred func(val interface{}) bool) Set {
+	m := make(map[interface{}]struct{})
+	for val := range s.load() {
+		if pred(val) {
+			m[val] = setVal
+		}
+	}
+	return newCopyOnWriteOf(m)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set.
+func (s *CopyOnWriteSet) Map(fn func(val interface{}) interface{}) Set {
+	m := make(map[interface{}]struct{})
+	for val := range s.load() {
+		m[fn(val)] = setVal
+	}
+	return newCopyOnWriteOf(m)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. The values are visited in any order.
+func (s *CopyOnWriteSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the values
+// which satisfy pred and the second one contains the rest.
+func (s *CopyOnWriteSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	in, out := make(map[interface{}]struct{}), make(map[interface{}]struct{})
+	for val := range s.load() {
+		if pred(val) {
+			in[val] = setVal
+		} else {
+			out[val] = setVal
+		}
+	}
+	return newCopyOnWriteOf(in), newCopyOnWriteOf(out)
+}
+
+// AnyMatch returns true if at least one value satisfies pred.
+func (s *CopyOnWriteSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values satisfy pred. It returns true for an
+// empty set.
+func (s *CopyOnWriteSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of the values which satisfy pred.
+func (s *CopyOnWriteSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// snapshotOf returns the values of the given set. The snapshot of a
+// *CopyOnWriteSet is used directly, so it must not be modified.
+func snapshotOf(set Set) map[interface{}]struct{} {
+	if o, ok := set.(*CopyOnWriteSet); ok {
+		return o.load()
+	}
+	return toMap(set)
+}
+
+// Union returns a new *CopyOnWriteSet that contains all items from the
+// receiver set and all items from the given set. The given set can be any Set
+// implementation.
+func (s *CopyOnWriteSet) Union(set Set) Set {
+	m := maps.Clone(s.load())
+	maps.Copy(m, snapshotOf(set))
+	return newCopyOnWriteOf(m)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *CopyOnWriteSet that stores the common ones. The given set can be any Set
+// implementation.
+func (s *CopyOnWriteSet) Intersection(set Set) Set {
+	a, b := s.load(), snapshotOf(set)
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	m := make(map[interface{}]struct{})
+	for val := range a {
+		if _, ok := b[val]; ok {
+			m[val] = setVal
+		}
+	}
+	return newCopyOnWriteOf(m)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *CopyOnWriteSet. The given set can be any Set implementation.
+func (s *CopyOnWriteSet) Difference(set Set) Set {
+	o := snapshotOf(set)
+	m := make(map[interface{}]struct{})
+	for val := range s.load() {
+		if _, ok := o[val]; !ok {
+			m[val] = setVal
+		}
+	}
+	return newCopyOnWriteOf(m)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false.
+func (s *CopyOnWriteSet) IsSubset(set Set) bool {
+	return subsetOf(s.load(), snapshotOf(set))
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false.
+func (s *CopyOnWriteSet) IsSuperset(set Set) bool {
+	return subsetOf(snapshotOf(set), s.load())
+}
+
+// subsetOf returns true if all values of a exist in b.
+func subsetOf(a, b map[interface{}]struct{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for val := range a {
+		if _, ok := b[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint returns true if none of the items are present in the sets.
+func (s *CopyOnWriteSet) IsDisjoint(set Set) bool {
+	o := snapshotOf(set)
+	for val := range s.load() {
+		if _, ok := o[val]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks whether both sets contain exactly the same values.
+func (s *CopyOnWriteSet) Equal(set Set) bool {
+	a, b := s.load(), snapshotOf(set)
+	return len(a) == len(b) && subsetOf(a, b)
+}
+
+// SymmetricDifference returns a new *CopyOnWriteSet that contains the items
+// which only exist in one of the sets.
+func (s *CopyOnWriteSet) SymmetricDifference(set Set) Set {
+	a, b := s.load(), snapshotOf(set)
+	m := make(map[interface{}]struct{})
+	for val := range a {
+		if _, ok := b[val]; !ok {
+			m[val] = setVal
+		}
+	}
+	for val := range b {
+		if _, ok := a[val]; !ok {
+			m[val] = setVal
+		}
+	}
+	return newCopyOnWriteOf(m)
+}
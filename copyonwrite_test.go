@@ -0,0 +1,133 @@
+package set
+
+import (
+	"testing"
+)
+
+func TestCopyOnWriteSet_Basic(t *testing.T) {
+	s, ok := New(CopyOnWrite).(*CopyOnWriteSet)
+	if !ok {
+		t.Fatalf("New(CopyOnWrite) did not return *CopyOnWriteSet")
+	}
+	if !s.Empty() || s.Peek() != nil {
+		t.Errorf("new set is not empty")
+	}
+
+	s.Add(1)
+	s.Append(1, 2, 3, "str", nil)
+	checkSameSet(t, "After Append", s, NewOf(ThreadUnsafe, 1, 2, 3, "str", nil))
+
+	// The writes which do not change the set must not publish a new snapshot.
+	before := s.snap.Load()
+	s.Add(2)
+	s.Append(1, 3)
+	s.Remove("missing")
+	if s.snap.Load() != before {
+		t.Errorf("snapshot is replaced without a change")
+	}
+
+	s.Remove("str")
+	s.Remove(nil)
+	checkSameSet(t, "After Remove", s, NewOf(ThreadUnsafe, 1, 2, 3))
+	if len(*before) != 5 {
+		t.Errorf("old snapshot is modified: %v", *before)
+	}
+
+	if s.Peek() == nil {
+		t.Errorf("Peek returned nil for a non-empty set")
+	}
+	for i := 0; i < 3; i++ {
+		if _, ok := s.TryPop(); !ok {
+			t.Fatalf("TryPop failed at %v", i)
+		}
+	}
+	if val, ok := s.TryPop(); ok || s.Pop() != nil {
+		t.Errorf("expected empty set, popped %v", val)
+	}
+
+	s.Append(1, 2)
+	s.Clear()
+	if !s.Empty() || len(s.Slice()) != 0 {
+		t.Errorf("set is not empty after Clear")
+	}
+}
+
+func TestCopyOnWriteSet_ModifyWhileIterating(t *testing.T) {
+	s := NewCopyOnWrite()
+	s.Append(1, 2, 3)
+
+	var visited int
+	s.Each(func(val interface{}) bool {
+		s.Add(val.(int) + 10)
+		s.Remove(val)
+		visited++
+		return true
+	})
+	if visited != 3 {
+		t.Errorf("expected 3 visited values, actual %v", visited)
+	}
+	checkSameSet(t, "After iteration", s, NewOf(ThreadUnsafe, 11, 12, 13))
+}
+
+func TestCopyOnWriteSet_Functional(t *testing.T) {
+	s := NewCopyOnWrite()
+	s.Append(1, 2, 3, 4, 5, 6)
+
+	evens := s.Filter(isEven)
+	if _, ok := evens.(*CopyOnWriteSet); !ok {
+		t.Errorf("Filter returned %T", evens)
+	}
+	checkSameSet(t, "Filter", evens, NewOf(ThreadUnsafe, 2, 4, 6))
+	checkSameSet(t, "Map", s.Map(func(val interface{}) interface{} { return val.(int) % 3 }),
+		NewOf(ThreadUnsafe, 0, 1, 2))
+	in, out := s.Partition(isEven)
+	checkSameSet(t, "Partition in", in, NewOf(ThreadUnsafe, 2, 4, 6))
+	checkSameSet(t, "Partition out", out, NewOf(ThreadUnsafe, 1, 3, 5))
+
+	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+	if sum != 21 {
+		t.Errorf("expected sum 21, actual %v", sum)
+	}
+	if !s.AnyMatch(isEven) || s.AllMatch(isEven) || s.CountIf(isEven) != 3 {
+		t.Errorf("unexpected match results")
+	}
+}
+
+func TestCopyOnWriteSet_Operations(t *testing.T) {
+	others := []struct {
+		name string
+		set  Set
+	}{
+		{name: "CopyOnWrite", set: NewCopyOnWrite()},
+		{name: "ThreadSafe", set: New(ThreadSafe)},
+		{name: "ThreadUnsafe", set: New(ThreadUnsafe)},
+		{name: "Foreign", set: foreignSet{New(ThreadUnsafe)}},
+	}
+
+	for _, o := range others {
+		t.Run(o.name, func(t *testing.T) {
+			s := NewCopyOnWrite()
+			s.Append(1, 2, 3)
+			o.set.Append(2, 3, 4)
+
+			union := s.Union(o.set)
+			if _, ok := union.(*CopyOnWriteSet); !ok {
+				t.Errorf("Union returned %T", union)
+			}
+			checkSameSet(t, "Union", union, NewOf(ThreadUnsafe, 1, 2, 3, 4))
+			checkSameSet(t, "Intersection", s.Intersection(o.set), NewOf(ThreadUnsafe, 2, 3))
+			checkSameSet(t, "Difference", s.Difference(o.set), NewOf(ThreadUnsafe, 1))
+			checkSameSet(t, "SymmetricDifference", s.SymmetricDifference(o.set), NewOf(ThreadUnsafe, 1, 4))
+			if s.IsSubset(o.set) || s.IsSuperset(o.set) || s.IsDisjoint(o.set) || s.Equal(o.set) {
+				t.Errorf("unexpected comparison result")
+			}
+			checkSameSet(t, "Receiver after operations", s, NewOf(ThreadUnsafe, 1, 2, 3))
+
+			o.set.Remove(4)
+			o.set.Add(1)
+			if !s.IsSubset(o.set) || !s.IsSuperset(o.set) || !s.Equal(o.set) || !o.set.Equal(s) {
+				t.Errorf("expected equal sets")
+			}
+		})
+	}
+}
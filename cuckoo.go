@@ -0,0 +1,319 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+)
+
+// ErrFilterFull is returned when a value cannot be added into a CuckooFilter
+// because the filter is too full.
+var ErrFilterFull = errors.New("set: filter is full")
+
+const (
+	// cuckooBucketSize is the number of the fingerprints in a bucket.
+	cuckooBucketSize = 4
+
+	// cuckooMaxKicks is the number of the relocations which are tried before
+	// an insertion fails.
+	cuckooMaxKicks = 500
+)
+
+// CuckooFilter is a probabilistic set which keeps a small fingerprint of every
+// value, so the values can be removed unlike BloomFilter. Contains never
+// returns false for an added value, but it may return true for a value which
+// is not added. The rate of the false positives is about 8/2^f for the
+// fingerprints of f bits. It does not provide the thread-safety;
+// ThreadSafeCuckooFilter can be used instead.
+//
+// The filter keeps the fingerprints in buckets of 4 entries, and a value can
+// be stored in one of its 2 buckets. Adding a value fails with ErrFilterFull
+// when the filter is too full, which usually happens around 95% of its
+// capacity.
+//
+// Adding a value twice stores it twice, so it must be removed twice. Only the
+// added values should be removed, because removing a value which is not added
+// may remove another value with the same fingerprint.
+type CuckooFilter struct {
+	words   []uint64
+	buckets uint64
+	fpBits  uint
+	size    uint
+}
+
+// NewCuckooFilter creates a new empty *CuckooFilter with the given number of
+// buckets and fingerprint size in bits. The number of the buckets is rounded
+// up to a power of 2, and the filter can keep 4 values per bucket.
+// fingerprintBits must be between 1 and 32.
+//
+//	f := set.NewCuckooFilter(1<<16, 16)
+func NewCuckooFilter(buckets uint, fingerprintBits uint) *CuckooFilter {
+	if fingerprintBits < 1 || fingerprintBits > 32 {
+		panic(fmt.Sprintf("set: fingerprint size must be between 1 and 32, got %d", fingerprintBits))
+	}
+	n := uint64(1) << bits.Len64(uint64(max(buckets, 1))-1)
+	entries := n * cuckooBucketSize
+	return &CuckooFilter{
+		words:   make([]uint64, (entries*uint64(fingerprintBits)+wordSize-1)/wordSize),
+		buckets: n,
+		fpBits:  fingerprintBits,
+	}
+}
+
+// entry returns the fingerprint at the given entry index. The zero
+// fingerprint means an empty entry.
+func (f *CuckooFilter) entry(idx uint64) uint32 {
+	pos := idx * uint64(f.fpBits)
+	w, off := pos/wordSize, pos%wordSize
+	v := f.words[w] >> off
+	if off+uint64(f.fpBits) > wordSize {
+		v |= f.words[w+1] << (wordSize - off)
+	}
+	return uint32(v & (1<<f.fpBits - 1))
+}
+
+// setEntry stores fp at the given entry index.
+func (f *CuckooFilter) setEntry(idx uint64, fp uint32) {
+	pos := idx * uint64(f.fpBits)
+	w, off := pos/wordSize, pos%wordSize
+	mask := uint64(1)<<f.fpBits - 1
+	f.words[w] = f.words[w]&^(mask<<off) | uint64(fp)<<off
+	if off+uint64(f.fpBits) > wordSize {
+		shift := wordSize - off
+		f.words[w+1] = f.words[w+1]&^(mask>>shift) | uint64(fp)>>shift
+	}
+}
+
+// locate returns the fingerprint and the first bucket of val. It returns an
+// error which wraps ErrUnsupportedType if val cannot be hashed.
+func (f *CuckooFilter) locate(val interface{}) (uint32, uint64, error) {
+	h1, h2, err := hashValue(val)
+	if err != nil {
+		return 0, 0, err
+	}
+	fp := uint32(h2 & (1<<f.fpBits - 1))
+	if fp == 0 {
+		fp = 1
+	}
+	return fp, h1 & (f.buckets - 1), nil
+}
+
+// mustLocate returns the fingerprint and the first bucket of val like locate,
+// but it panics with the error of locate.
+func (f *CuckooFilter) mustLocate(val interface{}) (uint32, uint64) {
+	fp, i, err := f.locate(val)
+	if err != nil {
+		panic(err)
+	}
+	return fp, i
+}
+
+// altBucket returns the other bucket of the fingerprint fp which is in the
+// bucket i. It depends only on i and fp, so the buckets can be found while
+// relocating the fingerprints without the values.
+func (f *CuckooFilter) altBucket(i uint64, fp uint32) uint64 {
+	return (i ^ mix64(uint64(fp))) & (f.buckets - 1)
+}
+
+// insert stores fp in an empty entry of the bucket i. It returns false if the
+// bucket is full.
+func (f *CuckooFilter) insert(i uint64, fp uint32) bool {
+	for j := i * cuckooBucketSize; j < (i+1)*cuckooBucketSize; j++ {
+		if f.entry(j) == 0 {
+			f.setEntry(j, fp)
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the index of an entry of the bucket i which keeps fp.
+func (f *CuckooFilter) find(i uint64, fp uint32) (uint64, bool) {
+	for j := i * cuckooBucketSize; j < (i+1)*cuckooBucketSize; j++ {
+		if f.entry(j) == fp {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// Add adds val into the filter. It returns ErrFilterFull if there is no room
+// for val, and an error which wraps ErrUnsupportedType if val is a pointer, a
+// channel or a value which contains them. The filter is not changed in both
+// cases.
+//
+// Example:
+//	err := f.Add("session-id")
+func (f *CuckooFilter) Add(val interface{}) error {
+	fp, i1, err := f.locate(val)
+	if err != nil {
+		return err
+	}
+	i2 := f.altBucket(i1, fp)
+	if f.insert(i1, fp) || f.insert(i2, fp) {
+		f.size++
+		return nil
+	}
+
+	// Relocate the fingerprints to their other buckets. The replaced entries
+	// are recorded, so they can be restored if there is no room at the end.
+	type kick struct {
+		idx uint64
+		fp  uint32
+	}
+	var kicks []kick
+	i := i1
+	if rand.IntN(2) == 0 {
+		i = i2
+	}
+	for n := 0; n < cuckooMaxKicks; n++ {
+		j := i*cuckooBucketSize + uint64(rand.IntN(cuckooBucketSize))
+		old := f.entry(j)
+		kicks = append(kicks, kick{idx: j, fp: old})
+		f.setEntry(j, fp)
+		fp, i = old, f.altBucket(i, old)
+		if f.insert(i, fp) {
+			f.size++
+			return nil
+		}
+	}
+	for n := len(kicks) - 1; n >= 0; n-- {
+		f.setEntry(kicks[n].idx, kicks[n].fp)
+	}
+	return fmt.Errorf("%w: %d values in %d buckets", ErrFilterFull, f.size, f.buckets)
+}
+
+// Contains checks whether val may exist in the filter. It returns false only
+// if val is not added or it is removed. It panics with an error which wraps
+// ErrUnsupportedType if val cannot be added.
+//
+// Example:
+//	exist := f.Contains("session-id")
+func (f *CuckooFilter) Contains(val interface{}) bool {
+	fp, i1 := f.mustLocate(val)
+	if _, ok := f.find(i1, fp); ok {
+		return true
+	}
+	_, ok := f.find(f.altBucket(i1, fp), fp)
+	return ok
+}
+
+// Remove deletes val from the filter. It returns false if the filter does not
+// contain val. It panics with an error which wraps ErrUnsupportedType if val
+// cannot be added.
+//
+// Example:
+//	removed := f.Remove("session-id")
+func (f *CuckooFilter) Remove(val interface{}) bool {
+	fp, i1 := f.mustLocate(val)
+	j, ok := f.find(i1, fp)
+	if !ok {
+		j, ok = f.find(f.altBucket(i1, fp), fp)
+	}
+	if !ok {
+		return false
+	}
+	f.setEntry(j, 0)
+	f.size--
+	return true
+}
+
+// Size returns the number of the values in the filter.
+//
+// Example:
+//	size := f.Size()
+func (f *CuckooFilter) Size() uint {
+	return f.size
+}
+
+// Capacity returns the number of the entries in the filter. The filter
+// usually becomes full before all entries are used.
+//
+// Example:
+//	capacity := f.Capacity()
+func (f *CuckooFilter) Capacity() uint {
+	return uint(f.buckets * cuckooBucketSize)
+}
+
+// Empty checks whether the filter has no value.
+//
+// Example:
+//	empty := f.Empty()
+func (f *CuckooFilter) Empty() bool {
+	return f.size == 0
+}
+
+// Clear removes all values from the filter.
+//
+// Example:
+//	f.Clear()
+func (f *CuckooFilter) Clear() {
+	clear(f.words)
+	f.size = 0
+}
+
+// ThreadSafeCuckooFilter is a CuckooFilter which provides the thread-safety.
+type ThreadSafeCuckooFilter struct {
+	filter *CuckooFilter
+	rw     sync.RWMutex
+}
+
+// NewThreadSafeCuckooFilter creates a new empty *ThreadSafeCuckooFilter with
+// the same parameters with NewCuckooFilter.
+//
+//	f := set.NewThreadSafeCuckooFilter(1<<16, 16)
+func NewThreadSafeCuckooFilter(buckets uint, fingerprintBits uint) *ThreadSafeCuckooFilter {
+	return &ThreadSafeCuckooFilter{filter: NewCuckooFilter(buckets, fingerprintBits)}
+}
+
+// Add adds val into the filter. It returns ErrFilterFull if there is no room
+// for val.
+func (f *ThreadSafeCuckooFilter) Add(val interface{}) error {
+	f.rw.Lock()
+	defer f.rw.Unlock()
+	return f.filter.Add(val)
+}
+
+// Contains checks whether val may exist in the filter.
+func (f *ThreadSafeCuckooFilter) Contains(val interface{}) bool {
+	f.rw.RLock()
+	defer f.rw.RUnlock()
+	return f.filter.Contains(val)
+}
+
+// Remove deletes val from the filter. It returns false if the filter does not
+// contain val.
+func (f *ThreadSafeCuckooFilter) Remove(val interface{}) bool {
+	f.rw.Lock()
+	defer f.rw.Unlock()
+	return f.filter.Remove(val)
+}
+
+// Size returns the number of the values in the filter.
+func (f *ThreadSafeCuckooFilter) Size() uint {
+	f.rw.RLock()
+	defer f.rw.RUnlock()
+	return f.filter.Size()
+}
+
+// Capacity returns the number of the entries in the filter.
+func (f *ThreadSafeCuckooFilter) Capacity() uint {
+	// The capacity never changes, so it does not need the lock.
+	return f.filter.Capacity()
+}
+
+// Empty checks whether the filter has no value.
+func (f *ThreadSafeCuckooFilter) Empty() bool {
+	f.rw.RLock()
+	defer f.rw.RUnlock()
+	return f.filter.Empty()
+}
+
+// Clear removes all values from the filter.
+func (f *ThreadSafeCuckooFilter) Clear() {
+	f.rw.Lock()
+	defer f.rw.Unlock()
+	f.filter.Clear()
+}
@@ -0,0 +1,176 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCuckooFilter_AddContainsRemove(t *testing.T) {
+	testCases := []struct {
+		name    string
+		buckets uint
+		fpBits  uint
+		values  int
+	}{
+		{name: "Byte fingerprints", buckets: 256, fpBits: 8, values: 800},
+		{name: "Odd fingerprint size", buckets: 1000, fpBits: 13, values: 3000},
+		{name: "Wide fingerprints", buckets: 64, fpBits: 32, values: 200},
+		{name: "Single bucket", buckets: 1, fpBits: 16, values: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewCuckooFilter(tc.buckets, tc.fpBits)
+			for i := 0; i < tc.values; i++ {
+				if err := f.Add(fmt.Sprintf("session-%d", i)); err != nil {
+					t.Fatalf("unexpected error at %d: %v", i, err)
+				}
+			}
+			if f.Size() != uint(tc.values) {
+				t.Errorf("expected size %v, actual %v", tc.values, f.Size())
+			}
+			for i := 0; i < tc.values; i++ {
+				if !f.Contains(fmt.Sprintf("session-%d", i)) {
+					t.Fatalf("session-%d is not found", i)
+				}
+			}
+
+			for i := 0; i < tc.values; i += 2 {
+				if !f.Remove(fmt.Sprintf("session-%d", i)) {
+					t.Fatalf("session-%d is not removed", i)
+				}
+			}
+			for i := 1; i < tc.values; i += 2 {
+				if !f.Contains(fmt.Sprintf("session-%d", i)) {
+					t.Fatalf("session-%d is not found after removals", i)
+				}
+			}
+			if exp := uint(tc.values / 2); f.Size() != exp {
+				t.Errorf("expected size %v, actual %v", exp, f.Size())
+			}
+
+			f.Clear()
+			if !f.Empty() || f.Contains("session-1") {
+				t.Errorf("filter is not empty after Clear")
+			}
+		})
+	}
+}
+
+func TestCuckooFilter_FalsePositives(t *testing.T) {
+	f := NewCuckooFilter(1024, 16)
+	for i := 0; i < 3500; i++ {
+		if err := f.Add(i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var found int
+	for i := 0; i < 100000; i++ {
+		if f.Contains(fmt.Sprint(i)) {
+			found++
+		}
+	}
+	// The expected rate is about 8/2^16.
+	if found > 50 {
+		t.Errorf("too many false positives: %v", found)
+	}
+	if f.Remove("not added") {
+		t.Errorf("Remove returned true for a value which is not added")
+	}
+}
+
+func TestCuckooFilter_Full(t *testing.T) {
+	f := NewCuckooFilter(16, 16)
+	if f.Capacity() != 64 {
+		t.Fatalf("expected capacity 64, actual %v", f.Capacity())
+	}
+
+	var added []int
+	var err error
+	for i := 0; i < 100; i++ {
+		if err = f.Add(i); err != nil {
+			break
+		}
+		added = append(added, i)
+	}
+	if !errors.Is(err, ErrFilterFull) {
+		t.Fatalf("expected ErrFilterFull, actual %v", err)
+	}
+	if f.Size() != uint(len(added)) {
+		t.Errorf("expected size %v, actual %v", len(added), f.Size())
+	}
+	// A failed insertion must not lose the other values.
+	for _, val := range added {
+		if !f.Contains(val) {
+			t.Errorf("%v is lost after the failed insertion", val)
+		}
+	}
+}
+
+func TestCuckooFilter_Duplicates(t *testing.T) {
+	f := NewCuckooFilter(16, 16)
+	f.Add("a")
+	f.Add("a")
+	if f.Size() != 2 {
+		t.Errorf("expected size 2, actual %v", f.Size())
+	}
+	f.Remove("a")
+	if !f.Contains("a") {
+		t.Errorf("value is removed before its second copy")
+	}
+	f.Remove("a")
+	if f.Contains("a") {
+		t.Errorf("value is found after removing both copies")
+	}
+}
+
+func TestCuckooFilter_InvalidFingerprint(t *testing.T) {
+	for _, fpBits := range []uint{0, 33} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for fingerprint size %v", fpBits)
+				}
+			}()
+			NewCuckooFilter(16, fpBits)
+		}()
+	}
+}
+
+func TestThreadSafeCuckooFilter(t *testing.T) {
+	f := NewThreadSafeCuckooFilter(1024, 16)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				val := fmt.Sprintf("%d-%d", g, i)
+				if err := f.Add(val); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if !f.Contains(val) {
+					t.Errorf("%v is not found", val)
+				}
+				if i%2 == 0 {
+					f.Remove(val)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if f.Size() != 1000 {
+		t.Errorf("expected size 1000, actual %v", f.Size())
+	}
+	if f.Capacity() != 4096 {
+		t.Errorf("expected capacity 4096, actual %v", f.Capacity())
+	}
+	f.Clear()
+	if !f.Empty() {
+		t.Errorf("filter is not empty after Clear")
+	}
+}
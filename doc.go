@@ -41,5 +41,188 @@ You can get the symmetric difference of two sets by SymmetricDifference() method
 
 	// Returns a set which is the symmetric difference of the two sets.
 	symDiffSet := set1.SymmetricDifference(set2)
+
+You can create a sorted set with New(set.Ordered) or NewOrdered() with your own
+comparator. OrderedSet provides Min(), Max(), Floor(), Ceiling(), Range(),
+Rank() and Select() methods, and it visits the values in ascending order.
+
+	orderedSet := set.NewOrdered(nil)	// Uses set.Compare for sorting.
+	orderedSet.Append(5, 1, 3)
+	values := orderedSet.Range(2, 5)	// Returns [3 5].
+
+You can create a set which remembers the insertion order with
+New(set.InsertionOrdered) or NewInsertionOrdered(). Its Slice(), Each(), All()
+and Pop() methods follow the insertion order.
+
+	insertionOrderedSet := set.NewInsertionOrdered()
+	insertionOrderedSet.Append("c", "a", "b")
+	insertionOrderedSet.MoveToFront("b")	// Order is b, c, a.
+
+You can create a thread-safe set which scales with the concurrent writers with
+New(set.Sharded) or NewSharded(). It splits the values into the independently
+locked shards, so the writers of the different shards do not wait each other.
+
+	shardedSet := set.NewSharded(64)
+	shardedSet.Add(1)	// Locks only the shard of 1.
+
+You can create a thread-safe set whose reads do not take any lock with
+New(set.CopyOnWrite) or NewCopyOnWrite(). Every write copies the set, so it fits
+the sets which are read often and updated rarely.
+
+	allowList := set.NewCopyOnWrite()
+	allowList.Append("alice", "bob")	// Publishes a single new snapshot.
+	allowed := allowList.Contains("alice")	// Lock-free.
+
+You can keep the versions of a set with NewImmutable(). With() and Without()
+return new versions which share the unchanged parts with the old one, and
+NewImmutableBuilder() builds a large set without the intermediate versions.
+
+	v1 := set.NewImmutable(1, 2, 3)
+	v2 := v1.With(4).Without(1)	// v1 still contains 1, 2 and 3.
+
+You can create a thread-safe set whose values expire with NewExpiring(). The
+expired values are ignored by all methods and deleted lazily or by the optional
+janitor.
+
+	seen := set.NewExpiring(set.WithDefaultTTL(time.Minute), set.WithJanitor(time.Second))
+	defer seen.Stop()	// Stops the janitor.
+	seen.AddWithTTL("request-id", 10*time.Second)
+
+You can limit the size of a set with NewBounded(). When the set is full, its
+eviction policy rejects the new value or evicts an old one.
+
+	cache := set.NewBounded(set.ThreadSafe, 1000, set.EvictLRU, func(val interface{}) {
+		fmt.Println("evicted", val)
+	})
+	err := set.NewBounded(set.ThreadUnsafe, 1, set.EvictReject, nil).TryAdd(1)
+
+You can observe the changes of any set with NewObservable(). The handlers are
+only called for the actual changes, and they can be unsubscribed.
+
+	observed := set.NewObservable(set.New(set.ThreadSafe))
+	unsubscribe := observed.OnAdd(func(val interface{}) { fmt.Println("added", val) })
+	observed.Add(1)	// Prints "added 1".
+	observed.Add(1)	// Prints nothing.
+	unsubscribe()
+
+You can watch the changes of a thread-safe set with Watch(). The events are
+numbered in the order of the changes, and the channel is closed when the context
+is cancelled. A slow watcher receives a ChangeResync event instead of the
+dropped events by default.
+
+	events := safeSet.Watch(ctx, set.WithWatchBuffer(1024))
+	for ev := range events {
+		fmt.Println(ev.Seq, ev.Kind, ev.Value)	// Prints "1 added 5".
+	}
+
+ThreadSafeSet and ThreadUnsafeSet are encoded as JSON arrays by encoding/json.
+EncodeJSON() and DecodeJSON() work with any set, and they can sort the values
+and decode the integers as int instead of float64.
+
+	data, err := set.EncodeJSON(s, set.WithSortedJSON())	// Returns [1,2,3].
+	err = set.DecodeJSON(data, s, set.WithJSONNumbers(set.JSONInt))
+
+ThreadSafeSet and ThreadUnsafeSet can be encoded with MarshalBinary() and
+encoding/gob. NewEncoder() and NewDecoder() stream any set in the same format
+without holding the encoded set in the memory. The values keep their types, so
+int(1) is decoded as int and uint8(1) as uint8.
+
+	enc := set.NewEncoder(file)
+	err := enc.Encode(s)
+
+You can store a set in a database column with SQLSet. It is written as a
+PostgreSQL array or a JSON array, and it is read from both of them.
+
+	_, err := db.Exec("UPDATE posts SET tags = $1", set.NewSQLSet(tags, set.PostgresArray))
+	var dst set.SQLSet	// dst.Valid is false for NULL.
+	err = db.QueryRow("SELECT tags FROM posts").Scan(&dst)
+
+You can print a set with fmt. The values are sorted with Compare, so the same
+set is always printed in the same way. The width limits the number of the
+printed values, %+v prints their types and %#v prints the Go syntax. The sets
+created by New, the typed sets, BitSet and RoaringBitmap are printed in this way.
+
+	s := set.NewOf(set.ThreadUnsafe, 3, 1, 2)
+	fmt.Println(s)	// Prints {1, 2, 3}.
+	fmt.Printf("%2v", s)	// Prints {1, 2, ... 1 more}.
+	fmt.Printf("%#v", s)	// Prints set.NewOf(set.ThreadUnsafe, 1, 2, 3).
+	fmt.Printf("%#v", set.NewTypedOf(set.ThreadSafe, "a"))	// Prints set.NewTypedOf[string](set.ThreadSafe, "a").
+
+You can create a compact set of non-negative integers with NewBitSet(). Every
+value takes a single bit and the values are returned as uint. Contains and the
+relations such as Equal compare the values as uint like the other sets.
+
+	bitSet := set.NewBitSet()
+	bitSet.Append(1, 5, 64)
+	next, ok := bitSet.NextSet(2)	// Returns 5 and true.
+	exist := bitSet.Contains(uint(5))	// Returns true, but Contains(5) returns false.
+
+You can create a compressed set of uint32 values with NewRoaringBitmap(). It is
+serialized in the Roaring portable format, so the data can be shared with the
+other Roaring implementations.
+
+	roaring := set.NewRoaringBitmap()
+	roaring.AddUint32(1 << 30)
+	roaring.RunOptimize()	// Compresses the consecutive values.
+	data, err := roaring.MarshalBinary()
+
+You can create a probabilistic set with NewBloomFilter() from the expected number
+of values and the false positive rate. It uses a fixed amount of memory, but
+Contains() may return true for the values which are not added.
+NewCountingBloomFilter() creates a larger filter which supports Remove().
+
+	bloom := set.NewBloomFilter(1000000, 0.01)
+	bloom.Add("https://example.com")
+	seen := bloom.Contains("https://example.com")	// Always true for the added values.
+
+You can create a probabilistic set which supports removals with
+NewCuckooFilter() from the number of buckets and the fingerprint size in bits.
+Its Add() method returns ErrFilterFull when there is no room for the value.
+NewThreadSafeCuckooFilter() creates the thread-safe version.
+
+	cuckoo := set.NewCuckooFilter(1<<16, 16)
+	err := cuckoo.Add("session-id")
+	removed := cuckoo.Remove("session-id")
+
+You can estimate the number of distinct values without keeping them with
+NewHyperLogLog() or NewHyperLogLogFromSet(). The sketches of the same precision
+can be merged for estimating the size of the union.
+
+	sketch1 := set.NewHyperLogLogFromSet(set1, 14)
+	sketch2 := set.NewHyperLogLogFromSet(set2, 14)
+	unionSize, err := sketch1.UnionEstimate(sketch2)
+
+You can count the repeated values with NewMultiSet(). It supports the
+ThreadSafe and ThreadUnsafe types like New().
+
+	bag := set.NewMultiSet(set.ThreadUnsafe)
+	bag.Append(1, 1, 2)
+	count := bag.Count(1)	// Returns 2.
+	top := bag.MostCommon(1)	// Returns [{1 2}].
+
+You can walk the set without copying it with Each() or All() methods. The
+thread-safe sets hold the read lock during the iteration, so the set must not be
+modified while iterating.
+
+	s.Each(func(val interface{}) bool {
+		fmt.Println(val)
+		return true	// Return false for stopping the iteration.
+	})
+
+	for val := range s.All() {
+		fmt.Println(val)
+	}
+
+You can create a generic set with NewTyped() function. The values of the set are
+stored with their own type, so there is no need for type assertion.
+
+	// Create new thread-safe set of ints
+	typedSet := set.NewTyped[int](set.ThreadSafe)
+
+You can convert between the generic sets and the Set interface with ToSet() and
+FromSet() functions.
+
+	s := set.ToSet[int](typedSet)
+	typedSet, err := set.FromSet[int](s)	// Returns error if any value is not int.
 */
 package set
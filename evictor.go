@@ -0,0 +1,235 @@
+package set
+
+import (
+	"container/heap"
+	"container/list"
+	"math/rand/v2"
+	"slices"
+)
+
+// EvictionPolicy decides what a bounded set does when a new value is added
+// while the set is full.
+type EvictionPolicy int
+
+const (
+	// EvictReject rejects the new value. TryAdd returns ErrCapacityExceeded
+	// and Add ignores the value.
+	EvictReject EvictionPolicy = iota
+
+	// EvictLRU evicts the least recently used value. Add and Contains count as
+	// a use.
+	EvictLRU
+
+	// EvictLFU evicts the least frequently used value. Add and Contains count
+	// as a use, and the least recently used value is evicted among the values
+	// with the same count.
+	EvictLFU
+
+	// EvictFIFO evicts the first added value.
+	EvictFIFO
+
+	// EvictRandom evicts a random value. The value is chosen when it is needed
+	// and it is kept until the set is changed, so Peek returns the value which
+	// is removed by the next Pop or evicted by the next Add.
+	EvictRandom
+)
+
+// boundedEntry is a value of a bounded set with the bookkeeping of its
+// evictor.
+type boundedEntry struct {
+	val   interface{}
+	elem  *list.Element
+	index int
+	count uint64
+	tick  uint64
+}
+
+// evictor keeps the entries of a bounded set in the order of an eviction
+// policy.
+type evictor interface {
+	// add registers a new entry.
+	add(e *boundedEntry)
+	// access records a use of the entry.
+	access(e *boundedEntry)
+	// remove unregisters the entry.
+	remove(e *boundedEntry)
+	// victim returns the entry which is evicted next. It returns nil if there
+	// is no entry.
+	victim() *boundedEntry
+	// each calls fn for every entry until fn returns false.
+	each(fn func(e *boundedEntry) bool)
+	// clear unregisters all entries.
+	clear()
+}
+
+// newEvictor creates the evictor of the policy.
+func newEvictor(policy EvictionPolicy) evictor {
+	switch policy {
+	case EvictLRU:
+		return &listEvictor{touch: true}
+	case EvictLFU:
+		return &lfuEvictor{}
+	case EvictRandom:
+		return &randomEvictor{}
+	default:
+		// The rejecting sets use the insertion order for Pop and Each.
+		return &listEvictor{}
+	}
+}
+
+// listEvictor keeps the entries in a list from the oldest to the newest. If
+// touch is true, the used entries are moved to the end.
+type listEvictor struct {
+	l     list.List
+	touch bool
+}
+
+func (ev *listEvictor) add(e *boundedEntry) {
+	e.elem = ev.l.PushBack(e)
+}
+
+func (ev *listEvictor) access(e *boundedEntry) {
+	if ev.touch {
+		ev.l.MoveToBack(e.elem)
+	}
+}
+
+func (ev *listEvictor) remove(e *boundedEntry) {
+	ev.l.Remove(e.elem)
+}
+
+func (ev *listEvictor) victim() *boundedEntry {
+	if f := ev.l.Front(); f != nil {
+		return f.Value.(*boundedEntry)
+	}
+	return nil
+}
+
+func (ev *listEvictor) each(fn func(e *boundedEntry) bool) {
+	for el := ev.l.Front(); el != nil; {
+		next := el.Next()
+		if !fn(el.Value.(*boundedEntry)) {
+			return
+		}
+		el = next
+	}
+}
+
+func (ev *listEvictor) clear() {
+	ev.l.Init()
+}
+
+// lfuEvictor keeps the entries in a min-heap of their use counts.
+type lfuEvictor struct {
+	entries lfuHeap
+	tick    uint64
+}
+
+func (ev *lfuEvictor) add(e *boundedEntry) {
+	ev.tick++
+	e.count, e.tick = 1, ev.tick
+	heap.Push(&ev.entries, e)
+}
+
+func (ev *lfuEvictor) access(e *boundedEntry) {
+	ev.tick++
+	e.count, e.tick = e.count+1, ev.tick
+	heap.Fix(&ev.entries, e.index)
+}
+
+func (ev *lfuEvictor) remove(e *boundedEntry) {
+	heap.Remove(&ev.entries, e.index)
+}
+
+func (ev *lfuEvictor) victim() *boundedEntry {
+	if len(ev.entries) == 0 {
+		return nil
+	}
+	return ev.entries[0]
+}
+
+func (ev *lfuEvictor) each(fn func(e *boundedEntry) bool) {
+	// The entries are copied, because fn may remove the entry and that
+	// reorders the slice.
+	for _, e := range slices.Clone(ev.entries) {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (ev *lfuEvictor) clear() {
+	ev.entries = nil
+}
+
+// lfuHeap orders the entries by their use counts and then by their last use.
+type lfuHeap []*boundedEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].tick < h[j].tick
+}
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *lfuHeap) Push(x any) {
+	e := x.(*boundedEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *lfuHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+// randomEvictor keeps the entries in a slice for picking a random one. The
+// picked entry is kept in chosen until an entry is added or removed.
+type randomEvictor struct {
+	entries []*boundedEntry
+	chosen  *boundedEntry
+}
+
+func (ev *randomEvictor) add(e *boundedEntry) {
+	ev.chosen = nil
+	e.index = len(ev.entries)
+	ev.entries = append(ev.entries, e)
+}
+
+func (ev *randomEvictor) access(*boundedEntry) {}
+
+func (ev *randomEvictor) remove(e *boundedEntry) {
+	ev.chosen = nil
+	last := len(ev.entries) - 1
+	ev.entries[e.index] = ev.entries[last]
+	ev.entries[e.index].index = e.index
+	ev.entries[last] = nil
+	ev.entries = ev.entries[:last]
+}
+
+func (ev *randomEvictor) victim() *boundedEntry {
+	if ev.chosen == nil && len(ev.entries) != 0 {
+		ev.chosen = ev.entries[rand.IntN(len(ev.entries))]
+	}
+	return ev.chosen
+}
+
+func (ev *randomEvictor) each(fn func(e *boundedEntry) bool) {
+	// The entries are copied, because fn may remove the entry and that
+	// reorders the slice.
+	for _, e := range slices.Clone(ev.entries) {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (ev *randomEvictor) clear() {
+	ev.entries, ev.chosen = nil, nil
+}
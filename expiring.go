@@ -0,0 +1,481 @@
+package set
+
+import (
+	"container/heap"
+	"iter"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time to ExpiringSet. It can be replaced with a
+// fake clock in the tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock which returns the real time.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ExpiringSet is a thread-safe set type whose values can expire. A value which
+// is added with a TTL vanishes after its deadline: Contains, Size and the other
+// methods ignore it. The expired values are deleted lazily by the methods which
+// take the write lock, by DeleteExpired or by the optional background janitor.
+//
+// The values which are added by Add or Append get the default TTL, which is
+// zero unless WithDefaultTTL is given. A zero or negative TTL means that the
+// value never expires. Adding an existing value replaces its deadline.
+//
+// The sets returned from the binary operations and the functional methods are
+// new *ExpiringSet with the same clock and default TTL. The values keep their
+// deadlines from the receiver set, and the other values get the default TTL.
+// They do not have a janitor or an eviction callback.
+type ExpiringSet struct {
+	items    map[interface{}]time.Time
+	expiries expiryHeap
+	rw       sync.RWMutex
+	ttl      time.Duration
+	clock    Clock
+	onEvict  func(val interface{})
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// ExpiringOption configures an ExpiringSet.
+type ExpiringOption func(s *ExpiringSet)
+
+// WithDefaultTTL sets the TTL of the values which are added by Add and Append.
+func WithDefaultTTL(ttl time.Duration) ExpiringOption {
+	return func(s *ExpiringSet) {
+		s.ttl = ttl
+	}
+}
+
+// WithClock sets the clock which is used for the deadlines.
+func WithClock(clock Clock) ExpiringOption {
+	return func(s *ExpiringSet) {
+		s.clock = clock
+	}
+}
+
+// WithJanitor starts a background goroutine which deletes the expired values
+// at every interval. The janitor is started by NewExpiring after all options
+// are applied, and it runs until Stop is called, so the set is not garbage
+// collected before that. It panics if interval is not positive.
+func WithJanitor(interval time.Duration) ExpiringOption {
+	if interval <= 0 {
+		panic("set: janitor interval must be positive")
+	}
+	return func(s *ExpiringSet) {
+		s.interval = interval
+	}
+}
+
+// WithEvictionCallback sets the function which is called with every expired
+// value when it is deleted. The deletion may happen later than the deadline of
+// the value. fn is called after the lock of the set is released, so it can use
+// the set.
+func WithEvictionCallback(fn func(val interface{})) ExpiringOption {
+	return func(s *ExpiringSet) {
+		s.onEvict = fn
+	}
+}
+
+// NewExpiring creates a new *ExpiringSet with the given options.
+//
+//	s := set.NewExpiring(set.WithDefaultTTL(time.Minute), set.WithJanitor(time.Second))
+//	defer s.Stop()
+func NewExpiring(opts ...ExpiringOption) *ExpiringSet {
+	s := &ExpiringSet{items: make(map[interface{}]time.Time), clock: systemClock{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.interval > 0 {
+		s.stop = make(chan struct{})
+		go s.janitor(s.interval)
+	}
+	return s
+}
+
+// expiry is a deadline of a value in expiryHeap.
+type expiry struct {
+	deadline time.Time
+	val      interface{}
+}
+
+// expiryHeap is a min-heap of the deadlines. It may keep the stale deadlines of
+// the removed or re-added values, so the deadlines are checked against the map
+// before deleting a value.
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x any)        { *h = append(*h, x.(expiry)) }
+func (h *expiryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// expired checks whether the deadline is passed at now. The zero deadline
+// never passes.
+func expired(deadline, now time.Time) bool {
+	return !deadline.IsZero() && !now.Before(deadline)
+}
+
+// deadlineOf returns the deadline of a value which is added at now with ttl.
+func deadlineOf(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// put stores val with the deadline. It is not thread-safe.
+func (s *ExpiringSet) put(val interface{}, deadline time.Time) {
+	s.items[val] = deadline
+	if deadline.IsZero() {
+		return
+	}
+	heap.Push(&s.expiries, expiry{deadline: deadline, val: val})
+	// Rebuild the heap when the stale deadlines dominate it.
+	if len(s.expiries) > 2*len(s.items)+64 {
+		s.expiries = s.expiries[:0]
+		for v, d := range s.items {
+			if !d.IsZero() {
+				s.expiries = append(s.expiries, expiry{deadline: d, val: v})
+			}
+		}
+		heap.Init(&s.expiries)
+	}
+}
+
+// deleteExpired deletes the values whose deadlines are passed at now and
+// returns them. It is not thread-safe.
+func (s *ExpiringSet) deleteExpired(now time.Time) []interface{} {
+	var evicted []interface{}
+	for len(s.expiries) > 0 && expired(s.expiries[0].deadline, now) {
+		e := heap.Pop(&s.expiries).(expiry)
+		if d, ok := s.items[e.val]; ok && d.Equal(e.deadline) {
+			delete(s.items, e.val)
+			evicted = append(evicted, e.val)
+		}
+	}
+	return evicted
+}
+
+// lock acquires the write lock after deleting the expired values. The returned
+// function releases the lock and calls the eviction callback.
+func (s *ExpiringSet) lock() func() {
+	s.rw.Lock()
+	evicted := s.deleteExpired(s.clock.Now())
+	return func() {
+		s.rw.Unlock()
+		s.notify(evicted)
+	}
+}
+
+// notify calls the eviction callback for the evicted values.
+func (s *ExpiringSet) notify(evicted []interface{}) {
+	if s.onEvict == nil {
+		return
+	}
+	for _, val := range evicted {
+		s.onEvict(val)
+	}
+}
+
+// janitor deletes the expired values at every interval until Stop is called.
+func (s *ExpiringSet) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.DeleteExpired()
+		}
+	}
+}
+
+// Stop stops the background janitor. It can be called multiple times, and it
+// does nothing if the set has no janitor.
+func (s *ExpiringSet) Stop() {
+	if s.stop == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// DeleteExpired deletes the expired values and calls the eviction callback
+// for them.
+func (s *ExpiringSet) DeleteExpired() {
+	unlock := s.lock()
+	unlock()
+}
+
+// AddWithTTL adds val which expires after ttl. A zero or negative ttl means
+// that val never expires.
+func (s *ExpiringSet) AddWithTTL(val interface{}, ttl time.Duration) {
+	unlock := s.lock()
+	defer unlock()
+	s.put(val, deadlineOf(s.clock.Now(), ttl))
+}
+
+// TTL returns the remaining time of val before it expires with true. It
+// returns zero for a value which never expires. If val does not exist, it
+// returns zero and false.
+func (s *ExpiringSet) TTL(val interface{}) (time.Duration, bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	now := s.clock.Now()
+	d, ok := s.items[val]
+	if !ok || expired(d, now) {
+		return 0, false
+	}
+	if d.IsZero() {
+		return 0, true
+	}
+	return d.Sub(now), true
+}
+
+// Add adds a new value to set with the default TTL.
+func (s *ExpiringSet) Add(val interface{}) {
+	s.AddWithTTL(val, s.ttl)
+}
+
+// Append adds multiple values into set with the default TTL.
+func (s *ExpiringSet) Append(values ...interface{}) {
+	unlock := s.lock()
+	defer unlock()
+	deadline := deadlineOf(s.clock.Now(), s.ttl)
+	for _, val := range values {
+		s.put(val, deadline)
+	}
+}
+
+// Remove deletes the given value. The eviction callback is not called for it.
+func (s *ExpiringSet) Remove(val interface{}) {
+	unlock := s.lock()
+	defer unlock()
+	delete(s.items, val)
+}
+
+// Contains checks the value whether exists in the set and it is not expired.
+func (s *ExpiringSet) Contains(val interface{}) bool {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	d, ok := s.items[val]
+	return ok && !expired(d, s.clock.Now())
+}
+
+// Size returns the number of the values which are not expired.
+func (s *ExpiringSet) Size() uint {
+	unlock := s.lock()
+	defer unlock()
+	return uint(len(s.items))
+}
+
+// Pop removes a random value which is not expired from the set and returns it.
+// If there is no such value, it returns nil. Use TryPop for distinguishing a
+// nil value from the empty set.
+func (s *ExpiringSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value which is not expired from the set and returns
+// it with true. If there is no such value, it returns nil and false.
+func (s *ExpiringSet) TryPop() (interface{}, bool) {
+	unlock := s.lock()
+	defer unlock()
+	for val := range s.items {
+		delete(s.items, val)
+		return val, true
+	}
+	return nil, false
+}
+
+// Peek returns a random value which is not expired without removing it. If
+// there is no such value, it returns nil.
+func (s *ExpiringSet) Peek() interface{} {
+	var peeked interface{}
+	s.Each(func(val interface{}) bool {
+		peeked = val
+		return false
+	})
+	return peeked
+}
+
+// Clear removes everything from the set. The eviction callback is not called
+// for the removed values.
+func (s *ExpiringSet) Clear() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.items = make(map[interface{}]time.Time)
+	s.expiries = nil
+}
+
+// Empty checks whether the set has no value which is not expired.
+func (s *ExpiringSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Slice returns the values which are not expired as a slice.
+func (s *ExpiringSet) Slice() []interface{} {
+	var values []interface{}
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	if values == nil {
+		values = []interface{}{}
+	}
+	return values
+}
+
+// Each calls fn for every value which is not expired until fn returns false.
+// The read lock is held during the iteration, so the set must not be modified
+// from fn.
+func (s *ExpiringSet) Each(fn func(val interface{}) bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	now := s.clock.Now()
+	for val, d := range s.items {
+		if expired(d, now) {
+			continue
+		}
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values which are not expired. It follows
+// the same rules with Each, so the set must not be modified from the loop body.
+func (s *ExpiringSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// empty returns a new empty set with the same clock and default TTL.
+func (s *ExpiringSet) empty() *ExpiringSet {
+	return &ExpiringSet{items: make(map[interface{}]time.Time), clock: s.clock, ttl: s.ttl}
+}
+
+// inherit copies the deadlines of the values of dst from s and returns dst.
+func (s *ExpiringSet) inherit(dst Set) Set {
+	d := dst.(*ExpiringSet)
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for val := range d.items {
+		if deadline, ok := s.items[val]; ok {
+			d.put(val, deadline)
+		}
+	}
+	return d
+}
+
+// Filter returns a new set that contains the values which satisfy pred. pred
+// is called under the read lock, so it must not modify the set.
+func (s *ExpiringSet) Filter(pred func(val interface{}) bool) Set {
+	return s.inherit(filter(s, s.empty(), pred))
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. The results get the default TTL. fn is called under the read lock, so it
+// must not modify the set.
+func (s *ExpiringSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, s.empty(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. The values are visited in any order. fn is
+// called under the read lock, so it must not modify the set.
+func (s *ExpiringSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the values
+// which satisfy pred and the second one contains the rest. pred is called under
+// the read lock, so it must not modify the set.
+func (s *ExpiringSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	in, out := partition(s, s.empty(), s.empty(), pred)
+	return s.inherit(in), s.inherit(out)
+}
+
+// AnyMatch returns true if at least one value satisfies pred. pred is called
+// under the read lock, so it must not modify the set.
+func (s *ExpiringSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values satisfy pred. It returns true for an
+// empty set. pred is called under the read lock, so it must not modify the
+// set.
+func (s *ExpiringSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of the values which satisfy pred. pred is called
+// under the read lock, so it must not modify the set.
+func (s *ExpiringSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *ExpiringSet that contains all items from the receiver
+// set and all items from the given set. The given set can be any Set
+// implementation.
+func (s *ExpiringSet) Union(set Set) Set {
+	return s.inherit(union(s.empty(), s, set))
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *ExpiringSet that stores the common ones. The given set can be any Set
+// implementation.
+func (s *ExpiringSet) Intersection(set Set) Set {
+	return s.inherit(intersection(s.empty(), s, set))
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *ExpiringSet. The given set can be any Set implementation.
+func (s *ExpiringSet) Difference(set Set) Set {
+	return s.inherit(difference(s.empty(), s, set))
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false.
+func (s *ExpiringSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false.
+func (s *ExpiringSet) IsSuperset(set Set) bool {
+	return isSubset(set, s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets.
+func (s *ExpiringSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values.
+func (s *ExpiringSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *ExpiringSet that contains the items which
+// only exist in one of the sets.
+func (s *ExpiringSet) SymmetricDifference(set Set) Set {
+	return s.inherit(symmetricDifference(s.empty(), s, set))
+}
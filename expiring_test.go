@@ -0,0 +1,251 @@
+package set
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only changes by Advance.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestExpiringSet_TTL(t *testing.T) {
+	clock := newFakeClock()
+	var evicted []interface{}
+	s := NewExpiring(WithClock(clock), WithEvictionCallback(func(val interface{}) {
+		evicted = append(evicted, val)
+	}))
+
+	s.AddWithTTL("short", time.Second)
+	s.AddWithTTL("long", time.Minute)
+	s.Add("forever")
+	s.AddWithTTL("negative", -time.Second)
+	checkSameSet(t, "Before expiry", s, NewOf(ThreadUnsafe, "short", "long", "forever", "negative"))
+
+	if ttl, ok := s.TTL("short"); !ok || ttl != time.Second {
+		t.Errorf("expected TTL 1s, actual %v %v", ttl, ok)
+	}
+	if ttl, ok := s.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("expected TTL 0 for a value without expiry, actual %v %v", ttl, ok)
+	}
+
+	clock.Advance(time.Second)
+	if s.Contains("short") {
+		t.Errorf("expired value is found")
+	}
+	if _, ok := s.TTL("short"); ok {
+		t.Errorf("TTL is found for an expired value")
+	}
+	if len(evicted) != 0 {
+		t.Errorf("Contains must not evict, evicted %v", evicted)
+	}
+	if len(s.Slice()) != 3 || s.AnyMatch(func(val interface{}) bool { return val == "short" }) {
+		t.Errorf("expired value is visited: %v", s.Slice())
+	}
+	if s.Size() != 3 {
+		t.Errorf("expected size 3, actual %v", s.Size())
+	}
+	if len(evicted) != 1 || evicted[0] != "short" {
+		t.Errorf("expected short to be evicted, actual %v", evicted)
+	}
+
+	// Adding a value again replaces its deadline.
+	s.AddWithTTL("long", 2*time.Minute)
+	clock.Advance(time.Minute)
+	s.DeleteExpired()
+	if !s.Contains("long") || len(evicted) != 1 {
+		t.Errorf("re-added value is expired with its old deadline, evicted %v", evicted)
+	}
+	clock.Advance(time.Minute)
+	s.DeleteExpired()
+	if s.Contains("long") || len(evicted) != 2 {
+		t.Errorf("expected long to be evicted, evicted %v", evicted)
+	}
+
+	// Removed values are not evicted later.
+	s.AddWithTTL("removed", time.Second)
+	s.Remove("removed")
+	clock.Advance(time.Second)
+	s.DeleteExpired()
+	if len(evicted) != 2 {
+		t.Errorf("removed value is evicted: %v", evicted)
+	}
+	checkSameSet(t, "After expiry", s, NewOf(ThreadUnsafe, "forever", "negative"))
+}
+
+func TestExpiringSet_DefaultTTL(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiring(WithClock(clock), WithDefaultTTL(time.Second))
+	s.Append(1, 2)
+	s.Add(3)
+	s.AddWithTTL(4, 0)
+
+	clock.Advance(500 * time.Millisecond)
+	s.Add(1)
+	clock.Advance(500 * time.Millisecond)
+	checkSameSet(t, "After default TTL", s, NewOf(ThreadUnsafe, 1, 4))
+	if s.Empty() {
+		t.Errorf("set is empty")
+	}
+	clock.Advance(time.Second)
+	checkSameSet(t, "After second TTL", s, NewOf(ThreadUnsafe, 4))
+
+	if val, ok := s.TryPop(); !ok || val != 4 {
+		t.Errorf("expected to pop 4, actual %v %v", val, ok)
+	}
+	if s.Pop() != nil || s.Peek() != nil || !s.Empty() {
+		t.Errorf("expected empty set")
+	}
+}
+
+func TestExpiringSet_Janitor(t *testing.T) {
+	clock := newFakeClock()
+	evicted := make(chan interface{}, 10)
+	s := NewExpiring(WithClock(clock), WithJanitor(time.Millisecond),
+		WithEvictionCallback(func(val interface{}) {
+			evicted <- val
+		}))
+	defer s.Stop()
+
+	s.AddWithTTL("id", time.Minute)
+	clock.Advance(time.Minute)
+	select {
+	case val := <-evicted:
+		if val != "id" {
+			t.Errorf("expected id to be evicted, actual %v", val)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("janitor did not evict the expired value")
+	}
+
+	s.Stop()
+	s.Stop()
+	NewExpiring().Stop()
+}
+
+func TestExpiringSet_JanitorBeforeOptions(t *testing.T) {
+	// The janitor must not run before the options after it are applied.
+	clock := newFakeClock()
+	evicted := make(chan interface{}, 10)
+	s := NewExpiring(WithJanitor(time.Nanosecond), WithClock(clock),
+		WithEvictionCallback(func(val interface{}) {
+			evicted <- val
+		}))
+	defer s.Stop()
+
+	s.AddWithTTL("id", time.Minute)
+	clock.Advance(time.Minute)
+	select {
+	case val := <-evicted:
+		if val != "id" {
+			t.Errorf("expected id to be evicted, actual %v", val)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("janitor did not evict the expired value")
+	}
+}
+
+func TestExpiringSet_InvalidJanitorInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for interval %v", interval)
+				}
+			}()
+			WithJanitor(interval)
+		}()
+	}
+}
+
+func TestExpiringSet_CallbackUsesSet(t *testing.T) {
+	clock := newFakeClock()
+	var s *ExpiringSet
+	var sizes []uint
+	s = NewExpiring(WithClock(clock), WithEvictionCallback(func(val interface{}) {
+		sizes = append(sizes, s.Size())
+	}))
+	s.AddWithTTL(1, time.Second)
+	s.Add(2)
+	clock.Advance(time.Second)
+	s.DeleteExpired()
+	if len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("unexpected sizes from the callback: %v", sizes)
+	}
+}
+
+func TestExpiringSet_Operations(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiring(WithClock(clock))
+	s.AddWithTTL(1, time.Second)
+	s.Append(2, 3)
+	other := NewOf(ThreadUnsafe, 2, 3, 4)
+
+	union := s.Union(other)
+	if _, ok := union.(*ExpiringSet); !ok {
+		t.Fatalf("Union returned %T", union)
+	}
+	checkSameSet(t, "Union", union, NewOf(ThreadUnsafe, 1, 2, 3, 4))
+	checkSameSet(t, "Intersection", s.Intersection(other), NewOf(ThreadUnsafe, 2, 3))
+	checkSameSet(t, "Difference", s.Difference(other), NewOf(ThreadUnsafe, 1))
+	checkSameSet(t, "SymmetricDifference", s.SymmetricDifference(other), NewOf(ThreadUnsafe, 1, 4))
+	evens, odds := s.Partition(isEven)
+	checkSameSet(t, "Partition", evens, NewOf(ThreadUnsafe, 2))
+	checkSameSet(t, "Filter", s.Filter(isEven), NewOf(ThreadUnsafe, 2))
+	if s.IsSubset(other) || s.IsSuperset(other) || s.IsDisjoint(other) || s.Equal(other) {
+		t.Errorf("unexpected comparison result")
+	}
+	if s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) }) != 6 {
+		t.Errorf("unexpected Reduce result")
+	}
+
+	// The values keep their deadlines from the receiver set.
+	clock.Advance(time.Second)
+	checkSameSet(t, "Union after expiry", union, NewOf(ThreadUnsafe, 2, 3, 4))
+	checkSameSet(t, "Partition after expiry", odds, NewOf(ThreadUnsafe, 3))
+	if !s.IsSubset(other) || !s.Equal(NewOf(ThreadUnsafe, 2, 3)) {
+		t.Errorf("expired value is compared")
+	}
+	if !s.AllMatch(func(val interface{}) bool { return val != 1 }) || s.CountIf(isEven) != 1 {
+		t.Errorf("expired value is matched")
+	}
+
+	s.Clear()
+	if !s.Empty() {
+		t.Errorf("set is not empty after Clear")
+	}
+}
+
+func TestExpiringSet_StaleDeadlines(t *testing.T) {
+	clock := newFakeClock()
+	s := NewExpiring(WithClock(clock))
+	for i := 0; i < 1000; i++ {
+		s.AddWithTTL("same", time.Hour)
+	}
+	if len(s.expiries) > 100 {
+		t.Errorf("stale deadlines are not compacted: %v", len(s.expiries))
+	}
+	clock.Advance(time.Hour)
+	if s.Size() != 0 {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
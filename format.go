@@ -0,0 +1,383 @@
+package set
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// setTypeNames are the names of the set types in the Go syntax.
+var setTypeNames = map[setType]string{
+	ThreadSafe:       "set.ThreadSafe",
+	ThreadUnsafe:     "set.ThreadUnsafe",
+	Ordered:          "set.Ordered",
+	InsertionOrdered: "set.InsertionOrdered",
+	Sharded:          "set.Sharded",
+	CopyOnWrite:      "set.CopyOnWrite",
+}
+
+// sortedValues returns the values of s sorted with Compare.
+func sortedValues(s Set) []interface{} {
+	values := s.Slice()
+	slices.SortFunc(values, Compare)
+	return values
+}
+
+// sortedTypedValues returns the values of s sorted with Compare.
+func sortedTypedValues[T comparable](s TypedSet[T]) []interface{} {
+	values := make([]interface{}, 0, s.Size())
+	s.Each(func(val T) bool {
+		values = append(values, val)
+		return true
+	})
+	slices.SortFunc(values, Compare)
+	return values
+}
+
+// formatSet writes values for the Format methods of the sets. The values are
+// written in braces and separated by commas like {1, 2, 3}.
+//
+// The width is the maximum number of the values which are written, and the
+// rest is summarized like {1, 2, ... 998 more}. %+v writes the types of the
+// values like {int(1), string("a")}, and %#v writes the Go syntax which
+// creates the set, which is returned by syntax. The other verbs and the
+// flags are applied to every value, so %x writes the numbers in hexadecimal.
+func formatSet(f fmt.State, verb rune, values []interface{}, syntax func() string) {
+	if verb == 'v' && f.Flag('#') {
+		io.WriteString(f, syntax())
+		return
+	}
+
+	shown := len(values)
+	if width, ok := f.Width(); ok && width < shown {
+		shown = width
+	}
+	valueFormat := valueFormatString(f, verb)
+	typed := verb == 'v' && f.Flag('+')
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, val := range values[:shown] {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if typed {
+			b.WriteString(typedString(val))
+		} else {
+			fmt.Fprintf(&b, valueFormat, val)
+		}
+	}
+	if more := len(values) - shown; more > 0 {
+		if shown > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "... %d more", more)
+	}
+	b.WriteByte('}')
+	f.Write([]byte(b.String()))
+}
+
+// valueFormatString returns the format of the values for the given verb. It
+// keeps the flags and the precision, but not the width which is used for the
+// truncation.
+func valueFormatString(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// typedString returns val with its type like int(1) or string("a").
+func typedString(val interface{}) string {
+	if val == nil {
+		return "nil"
+	}
+	if reflect.ValueOf(val).Kind() == reflect.String {
+		return fmt.Sprintf("%T(%q)", val, val)
+	}
+	return fmt.Sprintf("%T(%v)", val, val)
+}
+
+// callSyntax returns the Go syntax of a call of fn with the given arguments,
+// such as set.NewOf(set.ThreadUnsafe, 1, "a").
+func callSyntax(fn string, args ...string) string {
+	return fn + "(" + strings.Join(args, ", ") + ")"
+}
+
+// newOfSyntax returns the Go syntax which creates a set of the given type with
+// the values by NewOf.
+func newOfSyntax(t setType, values []interface{}) func() string {
+	return func() string {
+		args := []string{setTypeNames[t]}
+		for _, val := range values {
+			args = append(args, goSyntax(val))
+		}
+		return callSyntax("set.NewOf", args...)
+	}
+}
+
+// newTypedOfSyntax returns the Go syntax which creates a typed set of the given
+// type with the values by NewTypedOf.
+func newTypedOfSyntax[T comparable](t setType, values []interface{}) func() string {
+	return func() string {
+		args := []string{setTypeNames[t]}
+		for _, val := range values {
+			args = append(args, goSyntax(val))
+		}
+		return callSyntax("set.NewTypedOf["+reflect.TypeFor[T]().String()+"]", args...)
+	}
+}
+
+// integerSyntax returns the Go syntax which creates a set of the unsigned
+// integers by calling fn with the values in decimal.
+func integerSyntax(fn string, values []interface{}) func() string {
+	return func() string {
+		args := make([]string, len(values))
+		for i, val := range values {
+			args[i] = fmt.Sprint(val)
+		}
+		return callSyntax(fn, args...)
+	}
+}
+
+// goSyntax returns the Go syntax of val. The values which do not have the
+// default type of their literals are converted into their types, like int64(1)
+// or float32(1.5).
+func goSyntax(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "nil"
+	case bool, int, string, complex128:
+		return fmt.Sprintf("%#v", v)
+	case float64:
+		return goFloat(v, 64)
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%T(%s)", val, goFloat(v.Float(), v.Type().Bits()))
+	case reflect.Bool, reflect.String, reflect.Complex64, reflect.Complex128,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprintf("%T(%#v)", val, val)
+	}
+	return fmt.Sprintf("%#v", val)
+}
+
+// goFloat returns the Go syntax of f which is a floating-point literal, so 1 is
+// written as 1.0.
+func goFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	}
+	str := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string.
+func (s *ThreadSafeSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set.
+func (s *ThreadSafeSet) Format(f fmt.State, verb rune) {
+	values := sortedValues(s)
+	formatSet(f, verb, values, newOfSyntax(ThreadSafe, values))
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *ThreadUnsafeSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {1, 2, 3, ... 7 more}.
+func (s *ThreadUnsafeSet) Format(f fmt.State, verb rune) {
+	values := sortedValues(s)
+	formatSet(f, verb, values, newOfSyntax(ThreadUnsafe, values))
+}
+
+// String returns the values of the set like {1, 2, 3} in ascending order. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *OrderedSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are written in ascending order.
+// The width limits the number of the written values, %+v writes the types of
+// the values and %#v writes the Go syntax which creates the set with Compare.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {1, 2, 3, ... 7 more}.
+func (s *OrderedSet) Format(f fmt.State, verb rune) {
+	values := s.Slice()
+	formatSet(f, verb, values, newOfSyntax(Ordered, values))
+}
+
+// String returns the values of the set like {1, 2, 3} in insertion order. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *InsertionOrderedSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are written in insertion order.
+// The width limits the number of the written values, %+v writes the types of
+// the values and %#v writes the Go syntax which creates the set. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {c, a, b, ... 7 more}.
+func (s *InsertionOrderedSet) Format(f fmt.State, verb rune) {
+	values := s.Slice()
+	formatSet(f, verb, values, newOfSyntax(InsertionOrdered, values))
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string.
+func (s *ShardedSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set.
+func (s *ShardedSet) Format(f fmt.State, verb rune) {
+	values := sortedValues(s)
+	formatSet(f, verb, values, newOfSyntax(Sharded, values))
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string.
+func (s *CopyOnWriteSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set.
+func (s *CopyOnWriteSet) Format(f fmt.State, verb rune) {
+	values := sortedValues(s)
+	formatSet(f, verb, values, newOfSyntax(CopyOnWrite, values))
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string.
+func (s *ThreadSafeTypedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set.
+func (s *ThreadSafeTypedSet[T]) Format(f fmt.State, verb rune) {
+	values := sortedTypedValues[T](s)
+	formatSet(f, verb, values, newTypedOfSyntax[T](ThreadSafe, values))
+}
+
+// String returns the values of the set like {1, 2, 3}. The values are sorted
+// with Compare, so the same set always has the same string. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *ThreadUnsafeTypedSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are sorted with Compare. The
+// width limits the number of the written values, %+v writes the types of the
+// values and %#v writes the Go syntax which creates the set. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {1, 2, 3, ... 7 more}.
+func (s *ThreadUnsafeTypedSet[T]) Format(f fmt.State, verb rune) {
+	values := sortedTypedValues[T](s)
+	formatSet(f, verb, values, newTypedOfSyntax[T](ThreadUnsafe, values))
+}
+
+// String returns the values of the set like {1, 2, 3} in ascending order. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *BitSet) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are written in ascending order.
+// The width limits the number of the written values, %+v writes the types of
+// the values and %#v writes the Go syntax which creates the set with
+// NewBitSetOf. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {1, 2, 3, ... 7 more}.
+func (s *BitSet) Format(f fmt.State, verb rune) {
+	values := s.Slice()
+	formatSet(f, verb, values, integerSyntax("set.NewBitSetOf", values))
+}
+
+// String returns the values of the set like {1, 2, 3} in ascending order. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	str := s.String()
+func (s *RoaringBitmap) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are written in ascending order.
+// The width limits the number of the written values, %+v writes the types of
+// the values and %#v writes the Go syntax which creates the set with
+// NewRoaringBitmapOf. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	fmt.Printf("%3v", s)	// Prints {1, 2, 3, ... 7 more}.
+func (s *RoaringBitmap) Format(f fmt.State, verb rune) {
+	values := s.Slice()
+	formatSet(f, verb, values, integerSyntax("set.NewRoaringBitmapOf", values))
+}
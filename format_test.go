@@ -0,0 +1,176 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFormat_Verbs(t *testing.T) {
+	values := []interface{}{"b", 10, 2, true, nil, 1.5, "a", int64(3)}
+	testCases := []struct {
+		format string
+		exp    string
+	}{
+		{format: "%v", exp: "{<nil>, true, 1.5, 2, 3, 10, a, b}"},
+		{format: "%s", exp: "{%!s(<nil>), %!s(bool=true), %!s(float64=1.5), %!s(int=2), %!s(int64=3), %!s(int=10), a, b}"},
+		{format: "%3v", exp: "{<nil>, true, 1.5, ... 5 more}"},
+		{format: "%8v", exp: "{<nil>, true, 1.5, 2, 3, 10, a, b}"},
+		{format: "%+v", exp: `{nil, bool(true), float64(1.5), int(2), int64(3), int(10), string("a"), string("b")}`},
+		{format: "%+2v", exp: `{nil, bool(true), ... 6 more}`},
+		{format: "%#v", exp: `set.NewOf(set.ThreadUnsafe, nil, true, 1.5, 2, int64(3), 10, "a", "b")`},
+	}
+
+	for _, tc := range testCases {
+		s := NewOf(ThreadUnsafe, values...)
+		if str := fmt.Sprintf(tc.format, s); str != tc.exp {
+			t.Errorf("%s: expected %s, actual %s", tc.format, tc.exp, str)
+		}
+	}
+}
+
+func TestFormat_Types(t *testing.T) {
+	testCases := []struct {
+		name  string
+		set   Set
+		exp   string
+		expGo string
+	}{
+		{name: "ThreadSafe", set: New(ThreadSafe), exp: "{1, 2, 3}", expGo: "set.NewOf(set.ThreadSafe, 1, 2, 3)"},
+		{name: "ThreadUnsafe", set: New(ThreadUnsafe), exp: "{1, 2, 3}", expGo: "set.NewOf(set.ThreadUnsafe, 1, 2, 3)"},
+		{name: "Ordered", set: New(Ordered), exp: "{1, 2, 3}", expGo: "set.NewOf(set.Ordered, 1, 2, 3)"},
+		{name: "InsertionOrdered", set: New(InsertionOrdered), exp: "{3, 1, 2}", expGo: "set.NewOf(set.InsertionOrdered, 3, 1, 2)"},
+		{name: "Sharded", set: New(Sharded), exp: "{1, 2, 3}", expGo: "set.NewOf(set.Sharded, 1, 2, 3)"},
+		{name: "CopyOnWrite", set: New(CopyOnWrite), exp: "{1, 2, 3}", expGo: "set.NewOf(set.CopyOnWrite, 1, 2, 3)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			empty := tc.set.(fmt.Stringer).String()
+			if empty != "{}" {
+				t.Errorf("expected {}, actual %s", empty)
+			}
+			tc.set.Append(3, 1, 2)
+			if str := tc.set.(fmt.Stringer).String(); str != tc.exp {
+				t.Errorf("expected %s, actual %s", tc.exp, str)
+			}
+			if str := fmt.Sprintf("%#v", tc.set); str != tc.expGo {
+				t.Errorf("expected %s, actual %s", tc.expGo, str)
+			}
+		})
+	}
+}
+
+func TestFormat_OtherTypes(t *testing.T) {
+	safe := NewTyped[string](ThreadSafe)
+	safe.Append("b", "a")
+	testCases := []struct {
+		name   string
+		set    fmt.Stringer
+		exp    string
+		expGo  string
+		expTyp string
+	}{
+		{
+			name:   "ThreadUnsafeTyped",
+			set:    NewTypedOf(ThreadUnsafe, 3, 1, 2).(fmt.Stringer),
+			exp:    "{1, 2, 3}",
+			expGo:  "set.NewTypedOf[int](set.ThreadUnsafe, 1, 2, 3)",
+			expTyp: "{int(1), int(2), int(3)}",
+		},
+		{
+			name:   "ThreadSafeTyped",
+			set:    safe.(fmt.Stringer),
+			exp:    "{a, b}",
+			expGo:  `set.NewTypedOf[string](set.ThreadSafe, "a", "b")`,
+			expTyp: `{string("a"), string("b")}`,
+		},
+		{
+			name:   "Typed int64",
+			set:    NewTypedOf(ThreadUnsafe, int64(2)).(fmt.Stringer),
+			exp:    "{2}",
+			expGo:  "set.NewTypedOf[int64](set.ThreadUnsafe, int64(2))",
+			expTyp: "{int64(2)}",
+		},
+		{
+			name:   "BitSet",
+			set:    NewBitSetOf(64, 1, 3),
+			exp:    "{1, 3, 64}",
+			expGo:  "set.NewBitSetOf(1, 3, 64)",
+			expTyp: "{uint(1), uint(3), uint(64)}",
+		},
+		{
+			name:   "RoaringBitmap",
+			set:    NewRoaringBitmapOf(70000, 1),
+			exp:    "{1, 70000}",
+			expGo:  "set.NewRoaringBitmapOf(1, 70000)",
+			expTyp: "{uint32(1), uint32(70000)}",
+		},
+		{
+			name:   "Empty BitSet",
+			set:    NewBitSet(),
+			exp:    "{}",
+			expGo:  "set.NewBitSetOf()",
+			expTyp: "{}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if str := tc.set.String(); str != tc.exp {
+				t.Errorf("expected %s, actual %s", tc.exp, str)
+			}
+			if str := fmt.Sprintf("%#v", tc.set); str != tc.expGo {
+				t.Errorf("expected %s, actual %s", tc.expGo, str)
+			}
+			if str := fmt.Sprintf("%+v", tc.set); str != tc.expTyp {
+				t.Errorf("expected %s, actual %s", tc.expTyp, str)
+			}
+		})
+	}
+	if str := fmt.Sprintf("%1v", NewBitSetOf(1, 2, 3)); str != "{1, ... 2 more}" {
+		t.Errorf("unexpected truncated string %s", str)
+	}
+}
+
+func TestFormat_Deterministic(t *testing.T) {
+	s := New(ThreadSafe)
+	for i := 0; i < 100; i++ {
+		s.Append(i, fmt.Sprint(i))
+	}
+	exp := fmt.Sprint(s)
+	for i := 0; i < 10; i++ {
+		if str := fmt.Sprint(s.Union(New(ThreadUnsafe))); str != exp {
+			t.Fatalf("expected %s, actual %s", exp, str)
+		}
+	}
+	if str := fmt.Sprintf("%2v", s); str != "{0, 1, ... 198 more}" {
+		t.Errorf("unexpected truncated string %s", str)
+	}
+}
+
+func TestFormat_GoSyntax(t *testing.T) {
+	type named int
+	testCases := []struct {
+		val interface{}
+		exp string
+	}{
+		{val: nil, exp: "nil"},
+		{val: "a\"b", exp: `"a\"b"`},
+		{val: 1.0, exp: "1.0"},
+		{val: 1e21, exp: "1e+21"},
+		{val: math.NaN(), exp: "math.NaN()"},
+		{val: math.Inf(-1), exp: "math.Inf(-1)"},
+		{val: float32(0.1), exp: "float32(0.1)"},
+		{val: uint8(255), exp: "uint8(0xff)"},
+		{val: complex(1, 2), exp: "(1+2i)"},
+		{val: named(3), exp: "set.named(3)"},
+		{val: [2]int{1, 2}, exp: "[2]int{1, 2}"},
+	}
+
+	for _, tc := range testCases {
+		if str := goSyntax(tc.val); str != tc.exp {
+			t.Errorf("expected %s, actual %s", tc.exp, str)
+		}
+	}
+}
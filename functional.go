@@ -0,0 +1,79 @@
+package set
+
+// The functions in this file are the implementations of the functional methods
+// of the sets. They only walk the given set with Each, so the thread-safe sets
+// run them under a single read lock.
+
+// filter adds the values of s which satisfy pred into dst and returns dst.
+func filter(s Set, dst Set, pred func(val interface{}) bool) Set {
+	s.Each(func(val interface{}) bool {
+		if pred(val) {
+			dst.Add(val)
+		}
+		return true
+	})
+	return dst
+}
+
+// mapTo adds the results of fn for every value of s into dst and returns dst.
+func mapTo(s Set, dst Set, fn func(val interface{}) interface{}) Set {
+	s.Each(func(val interface{}) bool {
+		dst.Add(fn(val))
+		return true
+	})
+	return dst
+}
+
+// reduce folds the values of s into a single value by calling fn for every
+// value, starting with init.
+func reduce(s Set, init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	acc := init
+	s.Each(func(val interface{}) bool {
+		acc = fn(acc, val)
+		return true
+	})
+	return acc
+}
+
+// partition adds the values of s which satisfy pred into in and the others
+// into out.
+func partition(s Set, in, out Set, pred func(val interface{}) bool) (Set, Set) {
+	s.Each(func(val interface{}) bool {
+		if pred(val) {
+			in.Add(val)
+		} else {
+			out.Add(val)
+		}
+		return true
+	})
+	return in, out
+}
+
+// anyMatch returns true if at least one value of s satisfies pred.
+func anyMatch(s Set, pred func(val interface{}) bool) bool {
+	found := false
+	s.Each(func(val interface{}) bool {
+		found = pred(val)
+		return !found
+	})
+	return found
+}
+
+// allMatch returns true if all values of s satisfy pred.
+func allMatch(s Set, pred func(val interface{}) bool) bool {
+	return !anyMatch(s, func(val interface{}) bool {
+		return !pred(val)
+	})
+}
+
+// countIf returns the number of values of s which satisfy pred.
+func countIf(s Set, pred func(val interface{}) bool) uint {
+	var count uint
+	s.Each(func(val interface{}) bool {
+		if pred(val) {
+			count++
+		}
+		return true
+	})
+	return count
+}
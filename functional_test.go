@@ -0,0 +1,81 @@
+package set
+
+import (
+	"testing"
+)
+
+func isEven(val interface{}) bool {
+	return val.(int)%2 == 0
+}
+
+func TestSet_Functional(t *testing.T) {
+	testCases := []struct {
+		name    string
+		setType setType
+		expSafe bool
+	}{
+		{
+			name:    "Thread-safe set",
+			setType: ThreadSafe,
+			expSafe: true,
+		},
+		{
+			name:    "Thread-unsafe set",
+			setType: ThreadUnsafe,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(tc.setType)
+			s.Append(1, 2, 3, 4, 5)
+
+			checkSet(t, "filter", s.Filter(isEven), tc.expSafe, 2, 4)
+			checkSet(t, "map", s.Map(func(val interface{}) interface{} {
+				return val.(int) / 2
+			}), tc.expSafe, 0, 1, 2)
+
+			sum := s.Reduce(0, func(acc, val interface{}) interface{} {
+				return acc.(int) + val.(int)
+			})
+			if sum != 15 {
+				t.Errorf("expected sum 15, actual sum %v", sum)
+			}
+
+			evens, odds := s.Partition(isEven)
+			checkSet(t, "partition in", evens, tc.expSafe, 2, 4)
+			checkSet(t, "partition out", odds, tc.expSafe, 1, 3, 5)
+
+			if !s.AnyMatch(isEven) {
+				t.Errorf("expected any match")
+			}
+			if s.AllMatch(isEven) {
+				t.Errorf("expected not all match")
+			}
+			if count := s.CountIf(isEven); count != 2 {
+				t.Errorf("expected count 2, actual count %v", count)
+			}
+		})
+	}
+}
+
+func TestSet_FunctionalEmpty(t *testing.T) {
+	for _, st := range []setType{ThreadSafe, ThreadUnsafe} {
+		s := New(st)
+		if s.AnyMatch(isEven) {
+			t.Errorf("expected no match on empty set")
+		}
+		if !s.AllMatch(isEven) {
+			t.Errorf("expected all match on empty set")
+		}
+		if count := s.CountIf(isEven); count != 0 {
+			t.Errorf("expected count 0, actual count %v", count)
+		}
+		if acc := s.Reduce("init", nil); acc != "init" {
+			t.Errorf("expected init, actual %v", acc)
+		}
+		if !s.Filter(isEven).Empty() {
+			t.Errorf("expected empty set")
+		}
+	}
+}
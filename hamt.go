@@ -0,0 +1,372 @@
+package set
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"slices"
+)
+
+// The hash array mapped trie consumes hamtBits bits of the hash at every level.
+// The nodes below the last level are the collision nodes which keep the values
+// with the same hash in a list.
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtSeed is the seed of the hashes of ImmutableSet. It is shared by all sets,
+// so the sets can share their subtrees.
+var hamtSeed = maphash.MakeSeed()
+
+// hamtHash returns the hash of val in the trie.
+func hamtHash(val interface{}) uint64 {
+	return maphash.Comparable(hamtSeed, val)
+}
+
+// editToken marks the nodes which are created by a transient builder. The
+// nodes with the token of a builder can be modified in place by that builder.
+// It is not zero-sized, so every token has a distinct address.
+type editToken struct {
+	_ byte
+}
+
+// hamtNode is a node of the trie. A bit of bitmap is set for every occupied
+// slot of the level and entries keeps the slots in order. A collision node
+// keeps its values in entries and does not use bitmap. size is the number of
+// the values under the node.
+type hamtNode struct {
+	bitmap    uint32
+	entries   []hamtEntry
+	size      int
+	collision bool
+	edit      *editToken
+}
+
+// hamtEntry is either a subtree or a single value with its hash.
+type hamtEntry struct {
+	node *hamtNode
+	val  interface{}
+	hash uint64
+}
+
+// emptyHamt is the root of the empty sets. It has no edit token, so it is never
+// modified.
+var emptyHamt = &hamtNode{}
+
+// count returns the number of the values under the entry.
+func (e hamtEntry) count() int {
+	if e.node != nil {
+		return e.node.size
+	}
+	return 1
+}
+
+// hamtSlot returns the bit of the hash at the level of shift.
+func hamtSlot(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & hamtMask)
+}
+
+// index returns the position of the slot bit in entries.
+func (n *hamtNode) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// editable returns n if it belongs to edit, otherwise a copy of n which
+// belongs to edit.
+func (n *hamtNode) editable(edit *editToken) *hamtNode {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &hamtNode{
+		bitmap:    n.bitmap,
+		entries:   slices.Clone(n.entries),
+		size:      n.size,
+		collision: n.collision,
+		edit:      edit,
+	}
+}
+
+// contains checks whether the value with the hash exists under n.
+func (n *hamtNode) contains(hash uint64, val interface{}, shift uint) bool {
+	for {
+		if n.collision {
+			return slices.ContainsFunc(n.entries, func(e hamtEntry) bool { return e.val == val })
+		}
+		bit := hamtSlot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[n.index(bit)]
+		if e.node == nil {
+			return e.hash == hash && e.val == val
+		}
+		n, shift = e.node, shift+hamtBits
+	}
+}
+
+// insert adds the value entry e under n. It returns the new node and whether
+// the value is added. n itself is returned if the value already exists.
+func (n *hamtNode) insert(e hamtEntry, shift uint, edit *editToken) (*hamtNode, bool) {
+	if n.collision {
+		if slices.ContainsFunc(n.entries, func(x hamtEntry) bool { return x.val == e.val }) {
+			return n, false
+		}
+		c := n.editable(edit)
+		c.entries = append(c.entries, e)
+		c.size++
+		return c, true
+	}
+
+	bit := hamtSlot(e.hash, shift)
+	i := n.index(bit)
+	if n.bitmap&bit == 0 {
+		c := n.editable(edit)
+		c.entries = slices.Insert(c.entries, i, e)
+		c.bitmap |= bit
+		c.size++
+		return c, true
+	}
+
+	x := n.entries[i]
+	var child *hamtNode
+	switch {
+	case x.node != nil:
+		var added bool
+		if child, added = x.node.insert(e, shift+hamtBits, edit); !added {
+			return n, false
+		}
+	case x.hash == e.hash && x.val == e.val:
+		return n, false
+	default:
+		child = mergeLeaves(x, e, shift+hamtBits, edit)
+	}
+	c := n.editable(edit)
+	c.entries[i] = hamtEntry{node: child}
+	c.size++
+	return c, true
+}
+
+// mergeLeaves returns a new node at the level of shift which keeps the value
+// entries a and b.
+func mergeLeaves(a, b hamtEntry, shift uint, edit *editToken) *hamtNode {
+	if shift >= 64 {
+		return &hamtNode{entries: []hamtEntry{a, b}, size: 2, collision: true, edit: edit}
+	}
+	ba, bb := hamtSlot(a.hash, shift), hamtSlot(b.hash, shift)
+	if ba == bb {
+		child := mergeLeaves(a, b, shift+hamtBits, edit)
+		return &hamtNode{bitmap: ba, entries: []hamtEntry{{node: child}}, size: 2, edit: edit}
+	}
+	if ba > bb {
+		a, b = b, a
+	}
+	return &hamtNode{bitmap: ba | bb, entries: []hamtEntry{a, b}, size: 2, edit: edit}
+}
+
+// remove deletes the value with the hash under n. It returns the new node and
+// whether the value is removed. n itself is returned if the value does not
+// exist.
+func (n *hamtNode) remove(hash uint64, val interface{}, shift uint, edit *editToken) (*hamtNode, bool) {
+	if n.collision {
+		i := slices.IndexFunc(n.entries, func(e hamtEntry) bool { return e.val == val })
+		if i < 0 {
+			return n, false
+		}
+		c := n.editable(edit)
+		c.entries = slices.Delete(c.entries, i, i+1)
+		c.size--
+		return c, true
+	}
+
+	bit := hamtSlot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := n.index(bit)
+	x := n.entries[i]
+	if x.node == nil {
+		if x.hash != hash || x.val != val {
+			return n, false
+		}
+		c := n.editable(edit)
+		c.entries = slices.Delete(c.entries, i, i+1)
+		c.bitmap &^= bit
+		c.size--
+		return c, true
+	}
+
+	child, removed := x.node.remove(hash, val, shift+hamtBits, edit)
+	if !removed {
+		return n, false
+	}
+	c := n.editable(edit)
+	c.entries[i] = compact(child)
+	c.size--
+	return c, true
+}
+
+// compact returns the entry for the subtree n. A subtree with a single value is
+// replaced by the value, so the trie does not keep the long paths.
+func compact(n *hamtNode) hamtEntry {
+	if len(n.entries) == 1 && n.entries[0].node == nil {
+		return n.entries[0]
+	}
+	return hamtEntry{node: n}
+}
+
+// each calls fn for every value under n until fn returns false. It returns
+// false if fn returns false.
+func (n *hamtNode) each(fn func(val interface{}) bool) bool {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.each(fn) {
+				return false
+			}
+		} else if !fn(e.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// hamtUnion returns a node which keeps the values under a and b at the level
+// of shift. The subtrees which exist in only one of them or which are shared
+// by both are reused, and a or b itself is returned if the other one adds
+// nothing.
+func hamtUnion(a, b *hamtNode, shift uint) *hamtNode {
+	if a == b || b.size == 0 {
+		return a
+	}
+	if a.size == 0 {
+		return b
+	}
+	if a.collision {
+		c := a
+		for _, e := range b.entries {
+			c, _ = c.insert(e, shift, nil)
+		}
+		return c
+	}
+
+	u := &hamtNode{bitmap: a.bitmap | b.bitmap}
+	u.entries = make([]hamtEntry, 0, bits.OnesCount32(u.bitmap))
+	sameA, sameB := true, true
+	for rest := u.bitmap; rest != 0; rest &= rest - 1 {
+		bit := rest & -rest
+		var e hamtEntry
+		switch inA, inB := a.bitmap&bit != 0, b.bitmap&bit != 0; {
+		case !inB:
+			e, sameB = a.entries[a.index(bit)], false
+		case !inA:
+			e, sameA = b.entries[b.index(bit)], false
+		default:
+			ea, eb := a.entries[a.index(bit)], b.entries[b.index(bit)]
+			e = hamtUnionEntries(ea, eb, shift+hamtBits)
+			sameA = sameA && sameEntry(e, ea)
+			sameB = sameB && sameEntry(e, eb)
+		}
+		u.entries = append(u.entries, e)
+		u.size += e.count()
+	}
+	if sameA {
+		return a
+	}
+	if sameB {
+		return b
+	}
+	return u
+}
+
+// hamtUnionEntries returns the entry which keeps the values of the entries a
+// and b in the same slot at the level of shift.
+func hamtUnionEntries(a, b hamtEntry, shift uint) hamtEntry {
+	switch {
+	case a.node != nil && b.node != nil:
+		return hamtEntry{node: hamtUnion(a.node, b.node, shift)}
+	case a.node != nil:
+		n, _ := a.node.insert(b, shift, nil)
+		return hamtEntry{node: n}
+	case b.node != nil:
+		n, _ := b.node.insert(a, shift, nil)
+		return hamtEntry{node: n}
+	case a.hash == b.hash && a.val == b.val:
+		return a
+	default:
+		return hamtEntry{node: mergeLeaves(a, b, shift, nil)}
+	}
+}
+
+// sameEntry checks whether the entries are the same subtree or the same value.
+func sameEntry(a, b hamtEntry) bool {
+	if a.node != nil || b.node != nil {
+		return a.node == b.node
+	}
+	return a.hash == b.hash && a.val == b.val
+}
+
+// hamtIntersection returns a node which keeps the values which exist under
+// both a and b at the level of shift. a or b itself is returned if all of its
+// values exist in the other one.
+func hamtIntersection(a, b *hamtNode, shift uint) *hamtNode {
+	if a == b {
+		return a
+	}
+	if a.size == 0 || b.size == 0 {
+		return emptyHamt
+	}
+	if a.collision {
+		c := &hamtNode{collision: true}
+		for _, e := range a.entries {
+			if b.contains(e.hash, e.val, shift) {
+				c.entries = append(c.entries, e)
+			}
+		}
+		c.size = len(c.entries)
+		return sameSize(c, a, b)
+	}
+
+	n := &hamtNode{}
+	for rest := a.bitmap & b.bitmap; rest != 0; rest &= rest - 1 {
+		bit := rest & -rest
+		ea, eb := a.entries[a.index(bit)], b.entries[b.index(bit)]
+		var e hamtEntry
+		switch {
+		case ea.node != nil && eb.node != nil:
+			child := hamtIntersection(ea.node, eb.node, shift+hamtBits)
+			if child.size == 0 {
+				continue
+			}
+			e = compact(child)
+		case ea.node != nil:
+			if !ea.node.contains(eb.hash, eb.val, shift+hamtBits) {
+				continue
+			}
+			e = eb
+		case eb.node != nil:
+			if !eb.node.contains(ea.hash, ea.val, shift+hamtBits) {
+				continue
+			}
+			e = ea
+		case ea.hash == eb.hash && ea.val == eb.val:
+			e = ea
+		default:
+			continue
+		}
+		n.bitmap |= bit
+		n.entries = append(n.entries, e)
+		n.size += e.count()
+	}
+	return sameSize(n, a, b)
+}
+
+// sameSize returns a or b instead of n if n has the same size. n is a subset of
+// both, so it keeps the same values in that case.
+func sameSize(n, a, b *hamtNode) *hamtNode {
+	switch n.size {
+	case a.size:
+		return a
+	case b.size:
+		return b
+	}
+	return n
+}
@@ -0,0 +1,174 @@
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"reflect"
+)
+
+// hashValue returns two independent 64-bit hashes of val. The hashes do not
+// depend on the process, so they can be used for the serialized data. The
+// values are hashed in the way the map keys are compared: the type of val is a
+// part of the hash, so int(1) and int64(1) have different hashes, and the
+// negative zero has the hash of zero. The values of the types other than the
+// basic types, such as the structs and the arrays, are hashed by their types
+// and their fields or elements.
+//
+// The pointers and the channels are compared by their identity, which cannot
+// be hashed independently of the process, so it returns an error which wraps
+// ErrUnsupportedType for them and for the values which contain them. The slices,
+// the maps and the functions are not comparable, so they are rejected too.
+func hashValue(val interface{}) (uint64, uint64, error) {
+	h := fnv.New128a()
+	var buf [9]byte
+	switch v := val.(type) {
+	case nil:
+		buf[0] = 0
+		h.Write(buf[:1])
+	case bool:
+		buf[0], buf[1] = 1, 0
+		if v {
+			buf[1] = 1
+		}
+		h.Write(buf[:2])
+	case int:
+		writeTagged(h, buf[:], 2, uint64(v))
+	case int8:
+		writeTagged(h, buf[:], 3, uint64(v))
+	case int16:
+		writeTagged(h, buf[:], 4, uint64(v))
+	case int32:
+		writeTagged(h, buf[:], 5, uint64(v))
+	case int64:
+		writeTagged(h, buf[:], 6, uint64(v))
+	case uint:
+		writeTagged(h, buf[:], 7, uint64(v))
+	case uint8:
+		writeTagged(h, buf[:], 8, uint64(v))
+	case uint16:
+		writeTagged(h, buf[:], 9, uint64(v))
+	case uint32:
+		writeTagged(h, buf[:], 10, uint64(v))
+	case uint64:
+		writeTagged(h, buf[:], 11, v)
+	case float32:
+		writeTagged(h, buf[:], 12, uint64(math.Float32bits(normalizeFloat32(v))))
+	case float64:
+		writeTagged(h, buf[:], 13, math.Float64bits(normalizeFloat64(v)))
+	case string:
+		buf[0] = 14
+		h.Write(buf[:1])
+		h.Write([]byte(v))
+	default:
+		buf[0] = 0xff
+		h.Write(buf[:1])
+		if err := writeReflectValue(h, buf[:], reflect.ValueOf(v)); err != nil {
+			return 0, 0, err
+		}
+	}
+	sum := h.Sum(nil)
+	return mix64(binary.LittleEndian.Uint64(sum[:8])), mix64(binary.LittleEndian.Uint64(sum[8:])), nil
+}
+
+// mustHashValue returns the hashes of val like hashValue, but it panics with
+// the error of hashValue for the values which cannot be hashed.
+func mustHashValue(val interface{}) (uint64, uint64) {
+	h1, h2, err := hashValue(val)
+	if err != nil {
+		panic(err)
+	}
+	return h1, h2
+}
+
+// writeReflectValue writes the type and the value of v into w. The strings are
+// prefixed with their lengths and the interfaces with their dynamic types, so
+// the different values of the same type are written differently.
+func writeReflectValue(w io.Writer, buf []byte, v reflect.Value) error {
+	io.WriteString(w, v.Type().String())
+	buf[0] = 0
+	w.Write(buf[:1])
+
+	switch v.Kind() {
+	case reflect.Bool:
+		buf[0] = 0
+		if v.Bool() {
+			buf[0] = 1
+		}
+		w.Write(buf[:1])
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeTagged(w, buf, 0, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeTagged(w, buf, 0, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeTagged(w, buf, 0, math.Float64bits(normalizeFloat64(v.Float())))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeTagged(w, buf, 0, math.Float64bits(normalizeFloat64(real(c))))
+		writeTagged(w, buf, 0, math.Float64bits(normalizeFloat64(imag(c))))
+	case reflect.String:
+		writeTagged(w, buf, 0, uint64(v.Len()))
+		io.WriteString(w, v.String())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := writeReflectValue(w, buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := writeReflectValue(w, buf, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			buf[0] = 0
+			w.Write(buf[:1])
+			return nil
+		}
+		buf[0] = 1
+		w.Write(buf[:1])
+		return writeReflectValue(w, buf, v.Elem())
+	default:
+		return fmt.Errorf("%w: %s cannot be hashed", ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
+
+// normalizeFloat64 returns zero for the negative zero, because they are equal
+// as the map keys.
+func normalizeFloat64(f float64) float64 {
+	if f == 0 {
+		return 0
+	}
+	return f
+}
+
+// normalizeFloat32 returns zero for the negative zero like normalizeFloat64.
+func normalizeFloat32(f float32) float32 {
+	if f == 0 {
+		return 0
+	}
+	return f
+}
+
+// mix64 is the finalizer of MurmurHash3. FNV does not spread the last bytes
+// of the short values well, so its output is mixed before it is used.
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// writeTagged writes the type tag and the 64-bit value into w.
+func writeTagged(w io.Writer, buf []byte, tag byte, v uint64) {
+	buf[0] = tag
+	binary.LittleEndian.PutUint64(buf[1:], v)
+	w.Write(buf[:9])
+}
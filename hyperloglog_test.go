@@ -0,0 +1,124 @@
+package set
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// checkEstimate fails the test if the estimate is not within the relative
+// error tol of exp.
+func checkEstimate(t *testing.T, name string, est uint64, exp int, tol float64) {
+	t.Helper()
+	if math.Abs(float64(est)-float64(exp)) > tol*float64(max(exp, 1)) {
+		t.Errorf("%s: expected around %v, actual %v", name, exp, est)
+	}
+}
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		precision uint8
+		n         int
+		tol       float64
+	}{
+		{name: "Empty sketch", precision: 14, n: 0, tol: 0},
+		{name: "Small cardinality", precision: 14, n: 100, tol: 0.02},
+		{name: "Large cardinality", precision: 14, n: 200000, tol: 0.03},
+		{name: "Low precision", precision: 4, n: 10000, tol: 0.6},
+		{name: "High precision", precision: 18, n: 100000, tol: 0.01},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHyperLogLog(tc.precision)
+			for i := 0; i < tc.n; i++ {
+				h.Add(i)
+				h.Add(i) // Duplicates must not be counted.
+			}
+			checkEstimate(t, "Estimate", h.Estimate(), tc.n, tc.tol)
+			if h.Precision() != tc.precision {
+				t.Errorf("expected precision %v, actual %v", tc.precision, h.Precision())
+			}
+
+			h.Clear()
+			if h.Estimate() != 0 {
+				t.Errorf("expected 0 after Clear, actual %v", h.Estimate())
+			}
+		})
+	}
+}
+
+func TestHyperLogLog_InvalidPrecision(t *testing.T) {
+	for _, p := range []uint8{0, 3, 19} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for precision %v", p)
+				}
+			}()
+			NewHyperLogLog(p)
+		}()
+	}
+}
+
+func TestHyperLogLog_FromSet(t *testing.T) {
+	safe := New(ThreadSafe)
+	unsafe := New(ThreadUnsafe)
+	for i := 0; i < 30000; i++ {
+		safe.Add(i)
+		unsafe.Add(i + 20000)
+	}
+
+	a := NewHyperLogLogFromSet(safe, 14)
+	b := NewHyperLogLogFromSet(unsafe, 14)
+	checkEstimate(t, "Estimate", a.Estimate(), 30000, 0.03)
+
+	union, err := a.UnionEstimate(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEstimate(t, "UnionEstimate", union, 50000, 0.03)
+	checkEstimate(t, "Estimate after UnionEstimate", a.Estimate(), 30000, 0.03)
+
+	inter, err := a.IntersectionEstimate(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEstimate(t, "IntersectionEstimate", inter, 10000, 0.2)
+
+	disjoint := NewHyperLogLog(14)
+	disjoint.Append("a", "b", "c")
+	if inter, _ := disjoint.IntersectionEstimate(NewHyperLogLog(14)); inter != 0 {
+		t.Errorf("expected 0 intersection with an empty sketch, actual %v", inter)
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	a := NewHyperLogLog(12)
+	b := NewHyperLogLog(12)
+	a.Append("a", "b", "c")
+	b.Append("c", "d")
+
+	c := a.Clone()
+	if err := c.Merge(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Estimate() != 4 {
+		t.Errorf("expected 4 after Merge, actual %v", c.Estimate())
+	}
+	if a.Estimate() != 3 {
+		t.Errorf("Merge modified the cloned sketch")
+	}
+
+	other := NewHyperLogLog(10)
+	if err := a.Merge(other); !errors.Is(err, ErrIncompatibleFilter) {
+		t.Errorf("expected ErrIncompatibleFilter from Merge, actual %v", err)
+	}
+	if _, err := a.UnionEstimate(other); !errors.Is(err, ErrIncompatibleFilter) {
+		t.Errorf("expected ErrIncompatibleFilter from UnionEstimate, actual %v", err)
+	}
+	if _, err := a.IntersectionEstimate(other); !errors.Is(err, ErrIncompatibleFilter) {
+		t.Errorf("expected ErrIncompatibleFilter from IntersectionEstimate, actual %v", err)
+	}
+}
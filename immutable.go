@@ -0,0 +1,249 @@
+package set
+
+import "iter"
+
+// ImmutableSet is a persistent set type which is never modified. With and
+// Without return new versions of the set in O(log n) time, and the new versions
+// share the unchanged parts with the old one. It is backed by a hash array
+// mapped trie. Union and Intersection reuse the subtrees which are shared by
+// both sets or which exist in only one of them. ImmutableBuilder builds a set
+// from many values without creating the intermediate versions.
+//
+// An ImmutableSet can be read from multiple goroutines without any lock. The
+// zero value is an empty set.
+type ImmutableSet struct {
+	root *hamtNode
+}
+
+// NewImmutable creates a new *ImmutableSet with the given values.
+//
+//	s := set.NewImmutable(1, 2, 3)
+func NewImmutable(values ...interface{}) *ImmutableSet {
+	b := NewImmutableBuilder()
+	b.Append(values...)
+	return b.Build()
+}
+
+// node returns the root of the trie.
+func (s *ImmutableSet) node() *hamtNode {
+	if s.root == nil {
+		return emptyHamt
+	}
+	return s.root
+}
+
+// With returns a new version of the set which also contains val. It returns
+// the set itself if val already exists.
+//
+// Example:
+//	s2 := s.With("str")
+func (s *ImmutableSet) With(val interface{}) *ImmutableSet {
+	root, added := s.node().insert(hamtEntry{val: val, hash: hamtHash(val)}, 0, nil)
+	if !added {
+		return s
+	}
+	return &ImmutableSet{root: root}
+}
+
+// Without returns a new version of the set which does not contain val. It
+// returns the set itself if val does not exist.
+//
+// Example:
+//	s2 := s.Without("str")
+func (s *ImmutableSet) Without(val interface{}) *ImmutableSet {
+	root, removed := s.node().remove(hamtHash(val), val, 0, nil)
+	if !removed {
+		return s
+	}
+	return &ImmutableSet{root: root}
+}
+
+// Contains checks the value whether exists in the set.
+//
+// Example:
+//	exist := s.Contains("str")
+func (s *ImmutableSet) Contains(val interface{}) bool {
+	return s.node().contains(hamtHash(val), val, 0)
+}
+
+// Size returns the length of the set.
+//
+// Example:
+//	size := s.Size()
+func (s *ImmutableSet) Size() uint {
+	return uint(s.node().size)
+}
+
+// Empty checks whether the set is empty.
+//
+// Example:
+//	empty := s.Empty()
+func (s *ImmutableSet) Empty() bool {
+	return s.node().size == 0
+}
+
+// Slice returns the values of the set as a slice.
+//
+// Example:
+//	values := s.Slice()
+func (s *ImmutableSet) Slice() []interface{} {
+	values := make([]interface{}, 0, s.node().size)
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every value in the set until fn returns false. The values
+// are visited in the order of their hashes.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *ImmutableSet) Each(fn func(val interface{}) bool) {
+	s.node().each(fn)
+}
+
+// All returns an iterator over the values of the set.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ImmutableSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Union returns a set that contains all values from both sets. It reuses the
+// subtrees of both sets, so the union of a set with its own modified version
+// takes the time proportional to the number of the differences.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *ImmutableSet) Union(o *ImmutableSet) *ImmutableSet {
+	a, b := s.node(), o.node()
+	switch root := hamtUnion(a, b, 0); root {
+	case a:
+		return s
+	case b:
+		return o
+	default:
+		return &ImmutableSet{root: root}
+	}
+}
+
+// Intersection returns a set that contains the common values of both sets. The
+// subtrees which are shared by both sets are reused without visiting them.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *ImmutableSet) Intersection(o *ImmutableSet) *ImmutableSet {
+	a, b := s.node(), o.node()
+	switch root := hamtIntersection(a, b, 0); root {
+	case a:
+		return s
+	case b:
+		return o
+	default:
+		return &ImmutableSet{root: root}
+	}
+}
+
+// Equal checks whether both sets contain exactly the same values.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *ImmutableSet) Equal(o *ImmutableSet) bool {
+	a, b := s.node(), o.node()
+	if a == b {
+		return true
+	}
+	if a.size != b.size {
+		return false
+	}
+	return a.each(func(val interface{}) bool {
+		return b.contains(hamtHash(val), val, 0)
+	})
+}
+
+// Builder returns a new *ImmutableBuilder which starts with the values of the
+// set. The set is not modified by the builder.
+//
+// Example:
+//	b := s.Builder()
+func (s *ImmutableSet) Builder() *ImmutableBuilder {
+	return &ImmutableBuilder{root: s.node(), edit: new(editToken)}
+}
+
+// ImmutableBuilder is a transient version of ImmutableSet for adding or
+// removing many values. It modifies the nodes which it creates in place, so it
+// is faster than calling With or Without repeatedly. The sets returned from
+// Build are never modified by the builder. It does not provide the
+// thread-safety.
+type ImmutableBuilder struct {
+	root *hamtNode
+	edit *editToken
+}
+
+// NewImmutableBuilder creates a new empty *ImmutableBuilder.
+//
+//	b := set.NewImmutableBuilder()
+func NewImmutableBuilder() *ImmutableBuilder {
+	return &ImmutableBuilder{root: emptyHamt, edit: new(editToken)}
+}
+
+// Add adds a new value to the builder.
+//
+// Example:
+//	b.Add("str")
+func (b *ImmutableBuilder) Add(val interface{}) {
+	b.root, _ = b.root.insert(hamtEntry{val: val, hash: hamtHash(val)}, 0, b.edit)
+}
+
+// Append adds multiple values into the builder.
+//
+// Example:
+//	b.Append(1, 2, 3)
+func (b *ImmutableBuilder) Append(values ...interface{}) {
+	for _, val := range values {
+		b.Add(val)
+	}
+}
+
+// Remove deletes the given value from the builder.
+//
+// Example:
+//	b.Remove("str")
+func (b *ImmutableBuilder) Remove(val interface{}) {
+	b.root, _ = b.root.remove(hamtHash(val), val, 0, b.edit)
+}
+
+// Contains checks the value whether exists in the builder.
+//
+// Example:
+//	exist := b.Contains("str")
+func (b *ImmutableBuilder) Contains(val interface{}) bool {
+	return b.root.contains(hamtHash(val), val, 0)
+}
+
+// Size returns the number of the values in the builder.
+//
+// Example:
+//	size := b.Size()
+func (b *ImmutableBuilder) Size() uint {
+	return uint(b.root.size)
+}
+
+// Build returns an *ImmutableSet with the values of the builder. The builder
+// can be used after that, and its changes do not affect the returned set.
+//
+// Example:
+//	s := b.Build()
+func (b *ImmutableBuilder) Build() *ImmutableSet {
+	// The built nodes are given up, so the next changes copy them.
+	b.edit = new(editToken)
+	return &ImmutableSet{root: b.root}
+}
@@ -0,0 +1,226 @@
+package set
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkImmutable fails the test if s does not contain exactly the keys of exp.
+func checkImmutable(t *testing.T, name string, s *ImmutableSet, exp map[interface{}]struct{}) {
+	t.Helper()
+	if s.Size() != uint(len(exp)) {
+		t.Errorf("%s: expected size %v, actual size %v", name, len(exp), s.Size())
+	}
+	for val := range exp {
+		if !s.Contains(val) {
+			t.Errorf("%s: expected %v, but not exists", name, val)
+		}
+	}
+	if n := len(s.Slice()); n != len(exp) {
+		t.Errorf("%s: expected %v values from Slice, actual %v", name, len(exp), n)
+	}
+}
+
+func TestImmutableSet_WithWithout(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var versions []*ImmutableSet
+	var models []map[interface{}]struct{}
+
+	s := &ImmutableSet{}
+	model := map[interface{}]struct{}{}
+	for i := 0; i < 3000; i++ {
+		val := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			s = s.Without(val)
+			delete(model, val)
+		} else {
+			s = s.With(val)
+			model[val] = setVal
+		}
+		if i%300 == 0 {
+			versions = append(versions, s)
+			m := make(map[interface{}]struct{}, len(model))
+			for k := range model {
+				m[k] = setVal
+			}
+			models = append(models, m)
+		}
+	}
+	checkImmutable(t, "Last version", s, model)
+	// The old versions must not be affected by the later changes.
+	for i, v := range versions {
+		checkImmutable(t, "Old version", v, models[i])
+	}
+
+	for val := range model {
+		s = s.Without(val)
+	}
+	if !s.Empty() {
+		t.Errorf("set is not empty after removing all values")
+	}
+}
+
+func TestImmutableSet_Unchanged(t *testing.T) {
+	s := NewImmutable(1, 2, 3)
+	if s.With(2) != s {
+		t.Errorf("With returned a new set for an existing value")
+	}
+	if s.Without(4) != s {
+		t.Errorf("Without returned a new set for a missing value")
+	}
+
+	var zero ImmutableSet
+	if !zero.Empty() || zero.Contains(1) || zero.Without(1) != &zero {
+		t.Errorf("zero value is not an empty set")
+	}
+	if !zero.With(1).Contains(1) {
+		t.Errorf("With does not work on the zero value")
+	}
+}
+
+func TestImmutableSet_UnionIntersection(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		ba, bb := NewImmutableBuilder(), NewImmutableBuilder()
+		ma, mb := map[interface{}]struct{}{}, map[interface{}]struct{}{}
+		for j := 0; j < r.Intn(2000); j++ {
+			val := r.Intn(3000)
+			ba.Add(val)
+			ma[val] = setVal
+		}
+		for j := 0; j < r.Intn(2000); j++ {
+			val := r.Intn(3000)
+			bb.Add(val)
+			mb[val] = setVal
+		}
+		a, b := ba.Build(), bb.Build()
+
+		expUnion, expInter := map[interface{}]struct{}{}, map[interface{}]struct{}{}
+		for val := range ma {
+			expUnion[val] = setVal
+			if _, ok := mb[val]; ok {
+				expInter[val] = setVal
+			}
+		}
+		for val := range mb {
+			expUnion[val] = setVal
+		}
+		checkImmutable(t, "Union", a.Union(b), expUnion)
+		checkImmutable(t, "Intersection", a.Intersection(b), expInter)
+		if !a.Union(b).Equal(b.Union(a)) || !a.Intersection(b).Equal(b.Intersection(a)) {
+			t.Errorf("operations are not commutative")
+		}
+		checkImmutable(t, "Receiver", a, ma)
+	}
+}
+
+func TestImmutableSet_Sharing(t *testing.T) {
+	b := NewImmutableBuilder()
+	for i := 0; i < 10000; i++ {
+		b.Add(i)
+	}
+	s := b.Build()
+	modified := s.With(-1).Without(5)
+
+	if s.Union(s) != s || s.Intersection(s) != s {
+		t.Errorf("operations with the same set must return it")
+	}
+	if s.Union(NewImmutable(1, 2)) != s {
+		t.Errorf("Union with a subset must return the receiver")
+	}
+	if NewImmutable(1, 2).Union(s) != s {
+		t.Errorf("Union with a superset must return the argument")
+	}
+	small := NewImmutable(1, 2, -5)
+	if inter := s.Intersection(small); !inter.Equal(NewImmutable(1, 2)) {
+		t.Errorf("unexpected intersection %v", inter.Slice())
+	}
+
+	union := s.Union(modified)
+	if union.Size() != 10001 {
+		t.Errorf("expected size 10001, actual %v", union.Size())
+	}
+	inter := s.Intersection(modified)
+	if inter.Size() != 9999 || inter.Contains(5) || inter.Contains(-1) {
+		t.Errorf("unexpected intersection size %v", inter.Size())
+	}
+
+	// Most of the top-level subtrees are not touched by the modifications, so
+	// they must be shared by the versions.
+	var shared int
+	for i, e := range union.node().entries {
+		if e.node != nil && e.node == s.node().entries[i].node {
+			shared++
+		}
+	}
+	if shared < len(union.node().entries)-2 {
+		t.Errorf("expected shared subtrees, only %v of %v are shared", shared, len(union.node().entries))
+	}
+}
+
+func TestImmutableBuilder(t *testing.T) {
+	b := NewImmutableBuilder()
+	b.Append(1, 2, 3, 4)
+	b.Remove(4)
+	b.Remove(5)
+	if b.Size() != 3 || !b.Contains(1) || b.Contains(4) {
+		t.Errorf("unexpected builder values")
+	}
+
+	s := b.Build()
+	b.Add(10)
+	b.Remove(1)
+	checkImmutable(t, "Built set", s, map[interface{}]struct{}{1: setVal, 2: setVal, 3: setVal})
+	checkImmutable(t, "Second build", b.Build(), map[interface{}]struct{}{2: setVal, 3: setVal, 10: setVal})
+
+	fromSet := s.Builder()
+	fromSet.Add(7)
+	fromSet.Remove(2)
+	checkImmutable(t, "Source set", s, map[interface{}]struct{}{1: setVal, 2: setVal, 3: setVal})
+	checkImmutable(t, "Builder from set", fromSet.Build(), map[interface{}]struct{}{1: setVal, 3: setVal, 7: setVal})
+}
+
+func TestImmutableSet_Collisions(t *testing.T) {
+	// The entries are created with the same hash for testing the collision
+	// nodes, which are very rare with the real hashes.
+	const hash = 0xdeadbeef
+	entry := func(val interface{}) hamtEntry { return hamtEntry{val: val, hash: hash} }
+
+	a := emptyHamt
+	for _, val := range []interface{}{"a", "b", "c"} {
+		a, _ = a.insert(entry(val), 0, nil)
+	}
+	a, _ = a.insert(hamtEntry{val: "other", hash: 1}, 0, nil)
+	if a.size != 4 {
+		t.Fatalf("expected size 4, actual %v", a.size)
+	}
+	for _, val := range []interface{}{"a", "b", "c"} {
+		if !a.contains(hash, val, 0) {
+			t.Errorf("%v is not found", val)
+		}
+	}
+	if a.contains(hash, "d", 0) {
+		t.Errorf("d is found")
+	}
+
+	b := emptyHamt
+	for _, val := range []interface{}{"b", "c", "d"} {
+		b, _ = b.insert(entry(val), 0, nil)
+	}
+	if u := hamtUnion(a, b, 0); u.size != 5 || !u.contains(hash, "d", 0) {
+		t.Errorf("unexpected union size %v", u.size)
+	}
+	if i := hamtIntersection(a, b, 0); i.size != 2 || i.contains(hash, "a", 0) {
+		t.Errorf("unexpected intersection size %v", i.size)
+	}
+
+	a, _ = a.remove(hash, "a", 0, nil)
+	a, _ = a.remove(hash, "b", 0, nil)
+	if a.size != 2 || !a.contains(hash, "c", 0) || a.contains(hash, "b", 0) {
+		t.Errorf("unexpected values after remove")
+	}
+	// The single value of the collision node is moved up to the root.
+	if e := a.entries[a.index(hamtSlot(hash, 0))]; e.node != nil {
+		t.Errorf("single value is not compacted")
+	}
+}
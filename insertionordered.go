@@ -0,0 +1,441 @@
+package set
+
+import "iter"
+
+// InsertionOrderedSet is a set type which remembers the insertion order of its
+// values. Slice, Each, All and Pop follow the insertion order. Adding a value
+// which already exists does not change its position. It is backed by a map and
+// a doubly linked list, so Add, Remove and Contains take O(1) time. It does not
+// provide the thread-safety.
+//
+// The binary operations preserve the order of the receiver set and the new
+// values from the given set follow them in the order of the given set.
+type InsertionOrderedSet struct {
+	set         map[interface{}]*insertionNode
+	first, last *insertionNode
+}
+
+// insertionNode is a node of the linked list of InsertionOrderedSet.
+type insertionNode struct {
+	val        interface{}
+	prev, next *insertionNode
+}
+
+// NewInsertionOrdered creates a new *InsertionOrderedSet. It is same with
+// New(InsertionOrdered), but it returns the concrete type for calling the
+// methods which are not in the Set interface.
+//
+//	s := set.NewInsertionOrdered()
+func NewInsertionOrdered() *InsertionOrderedSet {
+	return &InsertionOrderedSet{set: make(map[interface{}]*insertionNode)}
+}
+
+// Add adds a new value to the end of the set. If the value already exists, its
+// position does not change. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	s.Add("str")
+func (s *InsertionOrderedSet) Add(val interface{}) {
+	if _, ok := s.set[val]; ok {
+		return
+	}
+	n := &insertionNode{val: val}
+	s.set[val] = n
+	s.pushBack(n)
+}
+
+// Append adds multiple values into the end of the set in the given order. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	s.Append(1, 2, 3, 4)
+func (s *InsertionOrderedSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Remove(2)
+func (s *InsertionOrderedSet) Remove(val interface{}) {
+	n, ok := s.set[val]
+	if !ok {
+		return
+	}
+	delete(s.set, val)
+	s.unlink(n)
+}
+
+// Contains checks the value whether exists in the set. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	exist := s.Contains(1)
+func (s *InsertionOrderedSet) Contains(val interface{}) bool {
+	_, ok := s.set[val]
+	return ok
+}
+
+// Size returns the length of the set which means that number of value of the set.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	size := s.Size()
+func (s *InsertionOrderedSet) Size() uint {
+	return uint(len(s.set))
+}
+
+// Pop removes the first inserted value from the set and returns it. If there
+// is no element in set, it returns nil. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	first := s.Pop()
+func (s *InsertionOrderedSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the first inserted value from the set and returns it with
+// true. If there is no element in set, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	first, ok := s.TryPop()
+func (s *InsertionOrderedSet) TryPop() (interface{}, bool) {
+	if s.first == nil {
+		return nil, false
+	}
+	val := s.first.val
+	s.Remove(val)
+	return val, true
+}
+
+// Peek returns the first inserted value without removing it. If there is no
+// element in set, it returns nil. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	first := s.Peek()
+func (s *InsertionOrderedSet) Peek() interface{} {
+	val, _ := s.First()
+	return val
+}
+
+// First returns the first value of the set with true. If there is no element
+// in set, it returns nil and false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	first, ok := s.First()
+func (s *InsertionOrderedSet) First() (interface{}, bool) {
+	if s.first == nil {
+		return nil, false
+	}
+	return s.first.val, true
+}
+
+// Last returns the last value of the set with true. If there is no element in
+// set, it returns nil and false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	last, ok := s.Last()
+func (s *InsertionOrderedSet) Last() (interface{}, bool) {
+	if s.last == nil {
+		return nil, false
+	}
+	return s.last.val, true
+}
+
+// MoveToFront moves the given value to the beginning of the set. It returns
+// false if the value does not exist. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	moved := s.MoveToFront("str")
+func (s *InsertionOrderedSet) MoveToFront(val interface{}) bool {
+	n, ok := s.set[val]
+	if !ok {
+		return false
+	}
+	s.unlink(n)
+	n.next = s.first
+	if s.first != nil {
+		s.first.prev = n
+	}
+	s.first = n
+	if s.last == nil {
+		s.last = n
+	}
+	return true
+}
+
+// MoveToBack moves the given value to the end of the set. It returns false if
+// the value does not exist. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	moved := s.MoveToBack("str")
+func (s *InsertionOrderedSet) MoveToBack(val interface{}) bool {
+	n, ok := s.set[val]
+	if !ok {
+		return false
+	}
+	s.unlink(n)
+	s.pushBack(n)
+	return true
+}
+
+// Clear removes everything from the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Clear()
+func (s *InsertionOrderedSet) Clear() {
+	s.set = make(map[interface{}]*insertionNode)
+	s.first, s.last = nil, nil
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	empty := s.Empty()
+func (s *InsertionOrderedSet) Empty() bool {
+	return len(s.set) == 0
+}
+
+// Slice returns the elements of the set as a slice in insertion order. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	setSlice := s.Slice()
+func (s *InsertionOrderedSet) Slice() []interface{} {
+	values := make([]interface{}, 0, len(s.set))
+	for n := s.first; n != nil; n = n.next {
+		values = append(values, n.val)
+	}
+	return values
+}
+
+// Each calls fn for every value in the set in insertion order until fn returns
+// false. fn may remove the value which is given to it, but it must not make
+// any other modifications. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *InsertionOrderedSet) Each(fn func(val interface{}) bool) {
+	for n := s.first; n != nil; {
+		next := n.next
+		if !fn(n.val) {
+			return
+		}
+		n = next
+	}
+}
+
+// All returns an iterator over the values of the set in insertion order. It
+// follows the same rules with Each. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *InsertionOrderedSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred in
+// insertion order. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *InsertionOrderedSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, NewInsertionOrdered(), pred)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set in insertion order. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	doubles := s.Map(func(val interface{}) interface{} { return val.(int) * 2 })
+func (s *InsertionOrderedSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, NewInsertionOrdered(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value in insertion order, starting with init. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+func (s *InsertionOrderedSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the
+// values which satisfy pred and the second one contains the others. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *InsertionOrderedSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, NewInsertionOrdered(), NewInsertionOrdered(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	hasNegative := s.AnyMatch(func(val interface{}) bool { return val.(int) < 0 })
+func (s *InsertionOrderedSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	allPositive := s.AllMatch(func(val interface{}) bool { return val.(int) > 0 })
+func (s *InsertionOrderedSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evenCount := s.CountIf(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *InsertionOrderedSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *InsertionOrderedSet that contains the items of the
+// receiver set in its order, followed by the new items of the given set. The
+// given set can be any Set implementation. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *InsertionOrderedSet) Union(set Set) Set {
+	return union(NewInsertionOrdered(), s, set)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *InsertionOrderedSet that stores them in the order of the receiver set. The
+// given set can be any Set implementation. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *InsertionOrderedSet) Intersection(set Set) Set {
+	return intersection(NewInsertionOrdered(), s, set)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *InsertionOrderedSet in the order of the receiver set. The given set can
+// be any Set implementation. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *InsertionOrderedSet) Difference(set Set) Set {
+	return difference(NewInsertionOrdered(), s, set)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *InsertionOrderedSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *InsertionOrderedSet) IsSuperset(set Set) bool {
+	if s.Size() < set.Size() {
+		return false
+	}
+	return set.AllMatch(s.Contains)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *InsertionOrderedSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values. The order of
+// the values is not compared. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *InsertionOrderedSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *InsertionOrderedSet that contains the items
+// which only exist in the receiver set in its order, followed by the items
+// which only exist in the given set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *InsertionOrderedSet) SymmetricDifference(set Set) Set {
+	symDiffSet := s.Difference(set)
+	for _, val := range set.Slice() {
+		if !s.Contains(val) {
+			symDiffSet.Add(val)
+		}
+	}
+	return symDiffSet
+}
+
+// pushBack links n to the end of the list.
+func (s *InsertionOrderedSet) pushBack(n *insertionNode) {
+	n.prev, n.next = s.last, nil
+	if s.last != nil {
+		s.last.next = n
+	} else {
+		s.first = n
+	}
+	s.last = n
+}
+
+// unlink removes n from the list.
+func (s *InsertionOrderedSet) unlink(n *insertionNode) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		s.first = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		s.last = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
@@ -0,0 +1,157 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewInsertionOrdered(t *testing.T) {
+	if _, ok := New(InsertionOrdered).(*InsertionOrderedSet); !ok {
+		t.Errorf("expected *InsertionOrderedSet")
+	}
+}
+
+func TestInsertionOrderedSet_Order(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		removes  []interface{}
+		front    interface{}
+		back     interface{}
+		expSlice []interface{}
+	}{
+		{
+			name:     "Insertion order",
+			values:   []interface{}{"c", 1, "a", true},
+			expSlice: []interface{}{"c", 1, "a", true},
+		},
+		{
+			name:     "Re-add keeps position",
+			values:   []interface{}{3, 1, 2, 3, 1},
+			expSlice: []interface{}{3, 1, 2},
+		},
+		{
+			name:     "Remove values",
+			values:   []interface{}{1, 2, 3, 4},
+			removes:  []interface{}{1, 3, 4, 10},
+			expSlice: []interface{}{2},
+		},
+		{
+			name:     "Move to front",
+			values:   []interface{}{1, 2, 3},
+			front:    3,
+			expSlice: []interface{}{3, 1, 2},
+		},
+		{
+			name:     "Move to back",
+			values:   []interface{}{1, 2, 3},
+			back:     1,
+			expSlice: []interface{}{2, 3, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewInsertionOrdered()
+			s.Append(tc.values...)
+			for _, val := range tc.removes {
+				s.Remove(val)
+			}
+			if tc.front != nil && !s.MoveToFront(tc.front) {
+				t.Errorf("expected %v is moved", tc.front)
+			}
+			if tc.back != nil && !s.MoveToBack(tc.back) {
+				t.Errorf("expected %v is moved", tc.back)
+			}
+
+			if values := s.Slice(); !reflect.DeepEqual(values, tc.expSlice) {
+				t.Errorf("expected %v, actual %v", tc.expSlice, values)
+			}
+			if first, _ := s.First(); first != tc.expSlice[0] {
+				t.Errorf("expected first %v, actual %v", tc.expSlice[0], first)
+			}
+			if last, _ := s.Last(); last != tc.expSlice[len(tc.expSlice)-1] {
+				t.Errorf("expected last %v, actual %v", tc.expSlice[len(tc.expSlice)-1], last)
+			}
+		})
+	}
+}
+
+func TestInsertionOrderedSet_Pop(t *testing.T) {
+	s := NewInsertionOrdered()
+	if _, ok := s.TryPop(); ok {
+		t.Errorf("expected false for empty set")
+	}
+	if s.MoveToFront(1) || s.MoveToBack(1) {
+		t.Errorf("expected false for missing value")
+	}
+	s.Append(3, 1, 2)
+	if val := s.Peek(); val != 3 {
+		t.Errorf("expected 3, actual %v", val)
+	}
+	for _, exp := range []interface{}{3, 1, 2} {
+		if val := s.Pop(); val != exp {
+			t.Errorf("expected %v, actual %v", exp, val)
+		}
+	}
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+	if _, ok := s.First(); ok {
+		t.Errorf("expected no first value")
+	}
+	s.Add(1)
+	if values := s.Slice(); !reflect.DeepEqual(values, []interface{}{1}) {
+		t.Errorf("expected [1], actual %v", values)
+	}
+}
+
+func TestInsertionOrderedSet_EachRemove(t *testing.T) {
+	s := NewInsertionOrdered()
+	s.Append(1, 2, 3, 4)
+	var visited []interface{}
+	s.Each(func(val interface{}) bool {
+		visited = append(visited, val)
+		s.Remove(val)
+		return true
+	})
+	if !reflect.DeepEqual(visited, []interface{}{1, 2, 3, 4}) || !s.Empty() {
+		t.Errorf("expected all values are visited and removed, actual %v", visited)
+	}
+}
+
+func TestInsertionOrderedSet_SetOperations(t *testing.T) {
+	s := NewInsertionOrdered()
+	s.Append(3, 1, 2)
+	o := NewInsertionOrdered()
+	o.Append(5, 2, 4, 3)
+
+	testCases := []struct {
+		name     string
+		result   Set
+		expSlice []interface{}
+	}{
+		{"Union", s.Union(o), []interface{}{3, 1, 2, 5, 4}},
+		{"Intersection", s.Intersection(o), []interface{}{3, 2}},
+		{"Difference", s.Difference(o), []interface{}{1}},
+		{"Symmetric difference", s.SymmetricDifference(o), []interface{}{1, 5, 4}},
+		{"Filter", s.Filter(func(val interface{}) bool { return val.(int) > 1 }), []interface{}{3, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := tc.result.(*InsertionOrderedSet); !ok {
+				t.Fatalf("expected *InsertionOrderedSet, actual %T", tc.result)
+			}
+			if values := tc.result.Slice(); !reflect.DeepEqual(values, tc.expSlice) {
+				t.Errorf("expected %v, actual %v", tc.expSlice, values)
+			}
+		})
+	}
+
+	other := New(ThreadSafe)
+	other.Append(1, 2, 3)
+	if !s.Equal(other) || !s.IsSubset(other) || !s.IsSuperset(other) || s.IsDisjoint(other) {
+		t.Errorf("expected equal sets")
+	}
+}
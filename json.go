@@ -0,0 +1,189 @@
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// JSONNumberMode defines the type of the numbers which are decoded from JSON.
+type JSONNumberMode uint8
+
+const (
+	// JSONFloat64 decodes all numbers as float64 like encoding/json. It is the
+	// default mode.
+	JSONFloat64 JSONNumberMode = iota
+	// JSONInt decodes the integers as int and the other numbers as float64.
+	// The integers which do not fit into int are decoded as float64.
+	JSONInt
+	// JSONInt64 decodes the integers as int64 and the other numbers as
+	// float64. The integers which do not fit into int64 are decoded as float64.
+	JSONInt64
+	// JSONNumber decodes all numbers as json.Number.
+	JSONNumber
+)
+
+// JSONOption configures EncodeJSON and DecodeJSON.
+type JSONOption func(c *jsonConfig)
+
+// jsonConfig is the configuration of EncodeJSON and DecodeJSON.
+type jsonConfig struct {
+	sorted  bool
+	numbers JSONNumberMode
+}
+
+// WithSortedJSON sorts the values with Compare while encoding, so the same set
+// is always encoded into the same JSON.
+func WithSortedJSON() JSONOption {
+	return func(c *jsonConfig) {
+		c.sorted = true
+	}
+}
+
+// WithJSONNumbers sets the type of the numbers which are decoded.
+func WithJSONNumbers(mode JSONNumberMode) JSONOption {
+	return func(c *jsonConfig) {
+		c.numbers = mode
+	}
+}
+
+// newJSONConfig returns the configuration of the given options.
+func newJSONConfig(opts []JSONOption) jsonConfig {
+	var c jsonConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// EncodeJSON returns the values of s as a JSON array. The values are in any
+// order unless WithSortedJSON is given.
+//
+//	data, err := set.EncodeJSON(s, set.WithSortedJSON())	// Returns [1,2,"a"].
+func EncodeJSON(s Set, opts ...JSONOption) ([]byte, error) {
+	return encodeJSON(s.Slice(), newJSONConfig(opts))
+}
+
+// DecodeJSON replaces the values of s with the values of the JSON array in
+// data. The arrays and the objects cannot be the values of a set, so it returns
+// an error wrapping ErrInvalidFormat for them. s is not changed if data cannot
+// be decoded, and it is not changed for JSON null.
+//
+//	err := set.DecodeJSON([]byte(`[1, 2, 3]`), s, set.WithJSONNumbers(set.JSONInt))
+func DecodeJSON(data []byte, s Set, opts ...JSONOption) error {
+	values, err := decodeJSON(data, newJSONConfig(opts))
+	if err != nil || values == nil {
+		return err
+	}
+	replaceValues(s, values)
+	return nil
+}
+
+// encodeJSON returns values as a JSON array.
+func encodeJSON(values []interface{}, c jsonConfig) ([]byte, error) {
+	if c.sorted {
+		slices.SortFunc(values, Compare)
+	}
+	return json.Marshal(values)
+}
+
+// decodeJSON returns the values of the JSON array in data. It returns nil for
+// JSON null and a non-nil slice for the empty array.
+func decodeJSON(data []byte, c jsonConfig) ([]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values []interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after JSON array", ErrInvalidFormat)
+	}
+	if values == nil {
+		return nil, nil
+	}
+
+	for i, val := range values {
+		switch v := val.(type) {
+		case json.Number:
+			n, err := decodeNumber(v, c.numbers)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = n
+		case []interface{}:
+			return nil, fmt.Errorf("%w: JSON array cannot be a set value", ErrInvalidFormat)
+		case map[string]interface{}:
+			return nil, fmt.Errorf("%w: JSON object cannot be a set value", ErrInvalidFormat)
+		}
+	}
+	return values, nil
+}
+
+// decodeNumber converts n into the type of the mode.
+func decodeNumber(n json.Number, mode JSONNumberMode) (interface{}, error) {
+	switch mode {
+	case JSONNumber:
+		return n, nil
+	case JSONInt:
+		if i, err := strconv.ParseInt(string(n), 10, strconv.IntSize); err == nil {
+			return int(i), nil
+		}
+	case JSONInt64:
+		if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return i, nil
+		}
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: JSON number %s is out of range", ErrInvalidFormat, n)
+	}
+	return f, nil
+}
+
+// MarshalJSON returns the values of the set as a JSON array in any order. Use
+// EncodeJSON for the sorted output.
+func (s *ThreadSafeSet) MarshalJSON() ([]byte, error) {
+	return encodeJSON(s.Slice(), jsonConfig{})
+}
+
+// UnmarshalJSON replaces the values of the set with the values of the JSON
+// array in data. The numbers are decoded as float64. Use DecodeJSON for
+// decoding them as the integers.
+func (s *ThreadSafeSet) UnmarshalJSON(data []byte) error {
+	values, err := decodeJSON(data, jsonConfig{})
+	if err != nil || values == nil {
+		return err
+	}
+	s.replace(values)
+	return nil
+}
+
+// MarshalJSON returns the values of the set as a JSON array in any order. Use
+// EncodeJSON for the sorted output. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	data, err := json.Marshal(s)
+func (s *ThreadUnsafeSet) MarshalJSON() ([]byte, error) {
+	return encodeJSON(s.Slice(), jsonConfig{})
+}
+
+// UnmarshalJSON replaces the values of the set with the values of the JSON
+// array in data. The numbers are decoded as float64. Use DecodeJSON for
+// decoding them as the integers. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	err := json.Unmarshal([]byte(`["a", "b"]`), s)
+func (s *ThreadUnsafeSet) UnmarshalJSON(data []byte) error {
+	values, err := decodeJSON(data, jsonConfig{})
+	if err != nil || values == nil {
+		return err
+	}
+	s.replace(values)
+	return nil
+}
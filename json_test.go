@@ -0,0 +1,160 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeJSON_Sorted(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []interface{}
+		exp    string
+	}{
+		{name: "Empty set", exp: `[]`},
+		{name: "Ints", values: []interface{}{3, 1, 2}, exp: `[1,2,3]`},
+		{name: "Mixed types", values: []interface{}{"b", 2, true, nil, 1.5, "a"}, exp: `[null,true,1.5,2,"a","b"]`},
+	}
+
+	for _, tc := range testCases {
+		for _, st := range []setType{ThreadSafe, ThreadUnsafe, Sharded} {
+			s := New(st)
+			s.Append(tc.values...)
+			data, err := EncodeJSON(s, WithSortedJSON())
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", tc.name, err)
+			}
+			if string(data) != tc.exp {
+				t.Errorf("%s: expected %s, actual %s", tc.name, tc.exp, data)
+			}
+		}
+	}
+}
+
+func TestDecodeJSON_Numbers(t *testing.T) {
+	testCases := []struct {
+		name string
+		mode JSONNumberMode
+		exp  []interface{}
+	}{
+		{name: "Float64", mode: JSONFloat64, exp: []interface{}{float64(1), 2.5, 1e20, "a"}},
+		{name: "Int", mode: JSONInt, exp: []interface{}{1, 2.5, 1e20, "a"}},
+		{name: "Int64", mode: JSONInt64, exp: []interface{}{int64(1), 2.5, 1e20, "a"}},
+		{name: "Number", mode: JSONNumber, exp: []interface{}{json.Number("1"), json.Number("2.5"), json.Number("100000000000000000000"), "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(ThreadUnsafe)
+			s.Add("old")
+			err := DecodeJSON([]byte(`[1, 2.5, 100000000000000000000, "a", 1]`), s, WithJSONNumbers(tc.mode))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			checkSameSet(t, tc.name, s, NewOf(ThreadUnsafe, tc.exp...))
+		})
+	}
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	values := []interface{}{0, -7, 42, "", "str", true, false, nil, 3.25}
+	for _, st := range []setType{ThreadSafe, ThreadUnsafe, Ordered, InsertionOrdered} {
+		s := New(st)
+		s.Append(values...)
+		data, err := EncodeJSON(s)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		decoded := New(st)
+		if err := DecodeJSON(data, decoded, WithJSONNumbers(JSONInt)); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if !decoded.Equal(s) {
+			t.Errorf("expected %v, actual %v", s.Slice(), decoded.Slice())
+		}
+	}
+}
+
+func TestJSON_Methods(t *testing.T) {
+	type document struct {
+		Safe   *ThreadSafeSet   `json:"safe"`
+		Unsafe *ThreadUnsafeSet `json:"unsafe"`
+	}
+
+	var doc document
+	if err := json.Unmarshal([]byte(`{"safe": ["a", 1], "unsafe": [true, null]}`), &doc); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkSameSet(t, "safe", doc.Safe, NewOf(ThreadUnsafe, "a", float64(1)))
+	checkSameSet(t, "unsafe", doc.Unsafe, NewOf(ThreadUnsafe, true, nil))
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var decoded document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkSameSet(t, "decoded safe", decoded.Safe, doc.Safe)
+	checkSameSet(t, "decoded unsafe", decoded.Unsafe, doc.Unsafe)
+
+	// JSON null does not change the sets.
+	if err := doc.Safe.UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := doc.Unsafe.UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if doc.Safe.Size() != 2 || doc.Unsafe.Size() != 2 {
+		t.Errorf("expected unchanged sets, actual %v and %v", doc.Safe.Slice(), doc.Unsafe.Slice())
+	}
+}
+
+func TestJSON_UnmarshalWatch(t *testing.T) {
+	s := newThreadSafeSet()
+	s.Append("a", "b")
+	events := s.Watch(t.Context())
+	if err := json.Unmarshal([]byte(`["b", "c"]`), s); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	exp := []ChangeEvent{{Kind: ChangeRemoved, Value: "a", Seq: 1}, {Kind: ChangeAdded, Value: "c", Seq: 2}}
+	if received := receive(t, events, len(exp)); !reflect.DeepEqual(received, exp) {
+		t.Errorf("expected %v, actual %v", exp, received)
+	}
+}
+
+func TestDecodeJSON_Invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      string
+		expFormat bool
+	}{
+		{name: "Object", data: `{"a": 1}`},
+		{name: "String", data: `"a"`},
+		{name: "Syntax error", data: `[1, 2`},
+		{name: "Nested array", data: `[1, [2]]`, expFormat: true},
+		{name: "Nested object", data: `[1, {}]`, expFormat: true},
+		{name: "Trailing data", data: `[1] [2]`, expFormat: true},
+		{name: "Out of range", data: `[1e400]`, expFormat: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(ThreadSafe)
+			s.Add("old")
+			err := DecodeJSON([]byte(tc.data), s)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if errors.Is(err, ErrInvalidFormat) != tc.expFormat {
+				t.Errorf("unexpected error %v", err)
+			}
+			if s.Size() != 1 || !s.Contains("old") {
+				t.Errorf("expected unchanged set, actual %v", s.Slice())
+			}
+		})
+	}
+}
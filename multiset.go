@@ -0,0 +1,162 @@
+package set
+
+import "slices"
+
+// MultiSet is a set type which counts the occurrences of its values, so adding
+// a value twice keeps it twice. Like Set, the binary operations accept any
+// MultiSet implementation and the multisets returned from them have the same
+// kind as the receiver. Each follows the same iteration rules with Set.
+//
+// Size returns the number of all occurrences, and Distinct returns the set of
+// the values without their counts.
+type MultiSet interface {
+	Membership
+	AddN(val interface{}, n uint)
+	Remove(val interface{})
+	RemoveAll(val interface{})
+	Count(val interface{}) uint
+	Size() uint
+	Clear()
+	Empty() bool
+	Distinct() Set
+	Each(fn func(val interface{}, count uint) bool)
+	MostCommon(k uint) []MultiSetEntry
+	Union(set MultiSet) MultiSet
+	Sum(set MultiSet) MultiSet
+	Intersection(set MultiSet) MultiSet
+	Difference(set MultiSet) MultiSet
+	Equal(set MultiSet) bool
+}
+
+// MultiSetEntry is a value of a MultiSet with its number of occurrences.
+type MultiSetEntry struct {
+	Value interface{}
+	Count uint
+}
+
+// NewMultiSet creates a multiset data structure regarding setType. Only
+// ThreadSafe and ThreadUnsafe are supported.
+//
+//	safeBag := set.NewMultiSet(set.ThreadSafe)
+//	unsafeBag := set.NewMultiSet(set.ThreadUnsafe)
+func NewMultiSet(t setType) MultiSet {
+	var set MultiSet
+	switch t {
+	case ThreadSafe:
+		set = newThreadSafeMultiSet()
+	case ThreadUnsafe:
+		set = newThreadUnsafeMultiSet()
+	}
+	return set
+}
+
+// toCounts copies the values of s with their counts into a new map.
+func toCounts(s MultiSet) map[interface{}]uint {
+	m := make(map[interface{}]uint)
+	s.Each(func(val interface{}, count uint) bool {
+		m[val] = count
+		return true
+	})
+	return m
+}
+
+// unionCounts stores the larger count of every value of a and b into dst and
+// returns the total count.
+func unionCounts(dst, a, b map[interface{}]uint) uint {
+	var size uint
+	for val, n := range a {
+		n = max(n, b[val])
+		dst[val] = n
+		size += n
+	}
+	for val, n := range b {
+		if _, ok := a[val]; !ok {
+			dst[val] = n
+			size += n
+		}
+	}
+	return size
+}
+
+// sumCounts stores the sum of the counts of every value of a and b into dst
+// and returns the total count.
+func sumCounts(dst, a, b map[interface{}]uint) uint {
+	var size uint
+	for val, n := range a {
+		dst[val] = n + b[val]
+		size += n + b[val]
+	}
+	for val, n := range b {
+		if _, ok := a[val]; !ok {
+			dst[val] = n
+			size += n
+		}
+	}
+	return size
+}
+
+// intersectionCounts stores the smaller count of every common value of a and
+// b into dst and returns the total count.
+func intersectionCounts(dst, a, b map[interface{}]uint) uint {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var size uint
+	for val, n := range a {
+		if m, ok := b[val]; ok {
+			n = min(n, m)
+			dst[val] = n
+			size += n
+		}
+	}
+	return size
+}
+
+// differenceCounts stores the counts of a minus the counts of b into dst and
+// returns the total count. The values whose counts drop to zero are dropped.
+func differenceCounts(dst, a, b map[interface{}]uint) uint {
+	var size uint
+	for val, n := range a {
+		if m := b[val]; m < n {
+			dst[val] = n - m
+			size += n - m
+		}
+	}
+	return size
+}
+
+// equalCounts checks whether a and b have the same values with the same
+// counts.
+func equalCounts(a, b map[interface{}]uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for val, n := range a {
+		if b[val] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// mostCommon returns at most k values of m with the largest counts. The values
+// with the same count are sorted by Compare, so the result is deterministic.
+func mostCommon(m map[interface{}]uint, k uint) []MultiSetEntry {
+	entries := make([]MultiSetEntry, 0, len(m))
+	for val, n := range m {
+		entries = append(entries, MultiSetEntry{Value: val, Count: n})
+	}
+	slices.SortFunc(entries, func(a, b MultiSetEntry) int {
+		if a.Count != b.Count {
+			if a.Count > b.Count {
+				return -1
+			}
+			return 1
+		}
+		return Compare(a.Value, b.Value)
+	})
+	if k < uint(len(entries)) {
+		entries = entries[:k]
+	}
+	return entries
+}
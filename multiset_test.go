@@ -0,0 +1,182 @@
+package set
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var multiSetTypes = []struct {
+	name string
+	t    setType
+}{
+	{name: "ThreadSafe", t: ThreadSafe},
+	{name: "ThreadUnsafe", t: ThreadUnsafe},
+}
+
+// newMultiSetOf creates a multiset of the given type with the given values.
+func newMultiSetOf(t setType, values ...interface{}) MultiSet {
+	s := NewMultiSet(t)
+	s.Append(values...)
+	return s
+}
+
+// checkCounts fails the test if s does not have exactly the given counts.
+func checkCounts(t *testing.T, name string, s MultiSet, exp map[interface{}]uint) {
+	t.Helper()
+	if actual := toCounts(s); !reflect.DeepEqual(actual, exp) {
+		t.Errorf("%s: expected %v, actual %v", name, exp, actual)
+	}
+	var size uint
+	for _, n := range exp {
+		size += n
+	}
+	if s.Size() != size {
+		t.Errorf("%s: expected size %v, actual %v", name, size, s.Size())
+	}
+	if s.Empty() != (size == 0) {
+		t.Errorf("%s: expected empty %v", name, size == 0)
+	}
+}
+
+func TestMultiSet_AddRemove(t *testing.T) {
+	for _, st := range multiSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			s := newMultiSetOf(st.t, 1, 1, 1, "a")
+			s.AddN("b", 3)
+			s.AddN("c", 0)
+			checkCounts(t, "After adding", s, map[interface{}]uint{1: 3, "a": 1, "b": 3})
+			if s.Count(1) != 3 || s.Count("c") != 0 {
+				t.Errorf("unexpected counts %v and %v", s.Count(1), s.Count("c"))
+			}
+			if !s.Contains("b") || s.Contains("c") {
+				t.Errorf("unexpected Contains result")
+			}
+
+			s.Remove(1)
+			s.Remove("a")
+			s.Remove("missing")
+			checkCounts(t, "After Remove", s, map[interface{}]uint{1: 2, "b": 3})
+
+			s.RemoveAll("b")
+			s.RemoveAll("missing")
+			checkCounts(t, "After RemoveAll", s, map[interface{}]uint{1: 2})
+
+			distinct := s.Distinct()
+			if distinct.Size() != 1 || !distinct.Contains(1) {
+				t.Errorf("unexpected Distinct result %v", distinct.Slice())
+			}
+			if _, ok := distinct.(*ThreadSafeSet); ok != (st.t == ThreadSafe) {
+				t.Errorf("Distinct returned %T", distinct)
+			}
+
+			s.Clear()
+			checkCounts(t, "After Clear", s, map[interface{}]uint{})
+		})
+	}
+}
+
+func TestMultiSet_Operations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     []interface{}
+		expUnion map[interface{}]uint
+		expSum   map[interface{}]uint
+		expInter map[interface{}]uint
+		expDiff  map[interface{}]uint
+	}{
+		{
+			name:     "Overlapping counts",
+			a:        []interface{}{1, 1, 1, 2, 3},
+			b:        []interface{}{1, 2, 2, 4},
+			expUnion: map[interface{}]uint{1: 3, 2: 2, 3: 1, 4: 1},
+			expSum:   map[interface{}]uint{1: 4, 2: 3, 3: 1, 4: 1},
+			expInter: map[interface{}]uint{1: 1, 2: 1},
+			expDiff:  map[interface{}]uint{1: 2, 3: 1},
+		},
+		{
+			name:     "Empty argument",
+			a:        []interface{}{"a", "a"},
+			expUnion: map[interface{}]uint{"a": 2},
+			expSum:   map[interface{}]uint{"a": 2},
+			expInter: map[interface{}]uint{},
+			expDiff:  map[interface{}]uint{"a": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, sa := range multiSetTypes {
+			for _, sb := range multiSetTypes {
+				t.Run(tc.name+"/"+sa.name+"/"+sb.name, func(t *testing.T) {
+					a := newMultiSetOf(sa.t, tc.a...)
+					b := newMultiSetOf(sb.t, tc.b...)
+					checkCounts(t, "Union", a.Union(b), tc.expUnion)
+					checkCounts(t, "Sum", a.Sum(b), tc.expSum)
+					checkCounts(t, "Intersection", a.Intersection(b), tc.expInter)
+					checkCounts(t, "Difference", a.Difference(b), tc.expDiff)
+
+					if _, ok := a.Union(b).(*ThreadSafeMultiSet); ok != (sa.t == ThreadSafe) {
+						t.Errorf("Union returned %T", a.Union(b))
+					}
+					if a.Equal(b) {
+						t.Errorf("different multisets are equal")
+					}
+					if !a.Equal(a.Union(NewMultiSet(sb.t))) {
+						t.Errorf("multiset is not equal to its copy")
+					}
+					if !a.Equal(a) {
+						t.Errorf("multiset is not equal to itself")
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestMultiSet_MostCommon(t *testing.T) {
+	for _, st := range multiSetTypes {
+		t.Run(st.name, func(t *testing.T) {
+			s := newMultiSetOf(st.t, "b", "a", "c", "a", "b", "d", "a")
+			testCases := []struct {
+				k   uint
+				exp []MultiSetEntry
+			}{
+				{k: 0, exp: []MultiSetEntry{}},
+				{k: 1, exp: []MultiSetEntry{{Value: "a", Count: 3}}},
+				{k: 3, exp: []MultiSetEntry{{Value: "a", Count: 3}, {Value: "b", Count: 2}, {Value: "c", Count: 1}}},
+				{k: 10, exp: []MultiSetEntry{{Value: "a", Count: 3}, {Value: "b", Count: 2}, {Value: "c", Count: 1}, {Value: "d", Count: 1}}},
+			}
+			for _, tc := range testCases {
+				if actual := s.MostCommon(tc.k); !reflect.DeepEqual(actual, tc.exp) {
+					t.Errorf("k=%d: expected %v, actual %v", tc.k, tc.exp, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadSafeMultiSet_Concurrent(t *testing.T) {
+	s := NewMultiSet(ThreadSafe)
+	other := newMultiSetOf(ThreadSafe, 1, 2)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				s.Add(i % 10)
+				s.Union(other)
+				other.Intersection(s)
+				s.Count(i % 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Size() != 8000 {
+		t.Errorf("expected size 8000, actual %v", s.Size())
+	}
+	if s.Count(3) != 800 {
+		t.Errorf("expected count 800, actual %v", s.Count(3))
+	}
+}
@@ -0,0 +1,204 @@
+package set
+
+import "sync"
+
+// ThreadSafeMultiSet is a multiset type which provides the thread-safety.
+type ThreadSafeMultiSet struct {
+	set  map[interface{}]uint
+	size uint
+	rw   sync.RWMutex
+}
+
+// newThreadSafeMultiSet creates a new *ThreadSafeMultiSet.
+func newThreadSafeMultiSet() *ThreadSafeMultiSet {
+	return &ThreadSafeMultiSet{set: make(map[interface{}]uint)}
+}
+
+// Add adds a single occurrence of val.
+func (s *ThreadSafeMultiSet) Add(val interface{}) {
+	s.AddN(val, 1)
+}
+
+// AddN adds n occurrences of val.
+func (s *ThreadSafeMultiSet) AddN(val interface{}, n uint) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.addN(val, n)
+}
+
+// addN is the implementation of the AddN method which is not thread-safety.
+// It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeMultiSet) addN(val interface{}, n uint) {
+	if n == 0 {
+		return
+	}
+	s.set[val] += n
+	s.size += n
+}
+
+// Append adds a single occurrence of every given value, so the repeated
+// values are counted.
+func (s *ThreadSafeMultiSet) Append(values ...interface{}) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	for _, val := range values {
+		s.addN(val, 1)
+	}
+}
+
+// Remove deletes a single occurrence of val.
+func (s *ThreadSafeMultiSet) Remove(val interface{}) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	n, ok := s.set[val]
+	if !ok {
+		return
+	}
+	if n == 1 {
+		delete(s.set, val)
+	} else {
+		s.set[val] = n - 1
+	}
+	s.size--
+}
+
+// RemoveAll deletes all occurrences of val.
+func (s *ThreadSafeMultiSet) RemoveAll(val interface{}) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.size -= s.set[val]
+	delete(s.set, val)
+}
+
+// Contains checks whether val occurs at least once.
+func (s *ThreadSafeMultiSet) Contains(val interface{}) bool {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	_, ok := s.set[val]
+	return ok
+}
+
+// Count returns the number of the occurrences of val.
+func (s *ThreadSafeMultiSet) Count(val interface{}) uint {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.set[val]
+}
+
+// Size returns the number of all occurrences in the multiset.
+func (s *ThreadSafeMultiSet) Size() uint {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.size
+}
+
+// Clear removes all values from the multiset.
+func (s *ThreadSafeMultiSet) Clear() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	clear(s.set)
+	s.size = 0
+}
+
+// Empty checks whether the multiset is empty.
+func (s *ThreadSafeMultiSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Distinct returns a new *ThreadSafeSet which contains the values of the
+// multiset without their counts.
+func (s *ThreadSafeMultiSet) Distinct() Set {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	d := newThreadSafeSet()
+	for val := range s.set {
+		d.add(val)
+	}
+	return d
+}
+
+// Each calls fn for every value with its count until fn returns false. The
+// read lock is held during the iteration, so the multiset must not be modified
+// from fn.
+func (s *ThreadSafeMultiSet) Each(fn func(val interface{}, count uint) bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for val, n := range s.set {
+		if !fn(val, n) {
+			return
+		}
+	}
+}
+
+// MostCommon returns at most k values with the largest counts in descending
+// order of their counts. The values with the same count are sorted by Compare.
+func (s *ThreadSafeMultiSet) MostCommon(k uint) []MultiSetEntry {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return mostCommon(s.set, k)
+}
+
+// lockWith acquires the read locks which are needed for the binary operations
+// and returns the counts of the given multiset with the function that releases
+// the locks. It follows the same rules with ThreadSafeSet.lockWith. The
+// returned map must not be modified.
+func (s *ThreadSafeMultiSet) lockWith(set MultiSet) (map[interface{}]uint, func()) {
+	if o, ok := set.(*ThreadSafeMultiSet); ok {
+		unlock := rLockBoth(&s.rw, &o.rw)
+		return o.set, unlock
+	}
+
+	o := toCounts(set)
+	s.rw.RLock()
+	return o, s.rw.RUnlock
+}
+
+// Union returns a new multiset which has every value with its larger count in
+// the two multisets. The returned multiset is always a *ThreadSafeMultiSet.
+func (s *ThreadSafeMultiSet) Union(set MultiSet) MultiSet {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+	u := newThreadSafeMultiSet()
+	u.size = unionCounts(u.set, s.set, o)
+	return u
+}
+
+// Sum returns a new multiset which has every value with the sum of its counts
+// in the two multisets. The returned multiset is always a *ThreadSafeMultiSet.
+func (s *ThreadSafeMultiSet) Sum(set MultiSet) MultiSet {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+	u := newThreadSafeMultiSet()
+	u.size = sumCounts(u.set, s.set, o)
+	return u
+}
+
+// Intersection returns a new multiset which has every common value with its
+// smaller count in the two multisets. The returned multiset is always a
+// *ThreadSafeMultiSet.
+func (s *ThreadSafeMultiSet) Intersection(set MultiSet) MultiSet {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+	u := newThreadSafeMultiSet()
+	u.size = intersectionCounts(u.set, s.set, o)
+	return u
+}
+
+// Difference returns a new multiset which has the counts of the receiver
+// minus the counts of the given multiset. The values whose counts drop to zero
+// are not kept. The returned multiset is always a *ThreadSafeMultiSet.
+func (s *ThreadSafeMultiSet) Difference(set MultiSet) MultiSet {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+	u := newThreadSafeMultiSet()
+	u.size = differenceCounts(u.set, s.set, o)
+	return u
+}
+
+// Equal checks whether both multisets have the same values with the same
+// counts.
+func (s *ThreadSafeMultiSet) Equal(set MultiSet) bool {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+	return equalCounts(s.set, o)
+}
@@ -0,0 +1,221 @@
+package set
+
+// ThreadUnsafeMultiSet is a multiset type which does not provide the
+// thread-safety.
+type ThreadUnsafeMultiSet struct {
+	set  map[interface{}]uint
+	size uint
+}
+
+// newThreadUnsafeMultiSet creates a new *ThreadUnsafeMultiSet.
+func newThreadUnsafeMultiSet() *ThreadUnsafeMultiSet {
+	return &ThreadUnsafeMultiSet{set: make(map[interface{}]uint)}
+}
+
+// Add adds a single occurrence of val. It is not a thread-safe method.
+//
+// Example:
+//	s.Add("str")
+func (s *ThreadUnsafeMultiSet) Add(val interface{}) {
+	s.AddN(val, 1)
+}
+
+// AddN adds n occurrences of val. It is not a thread-safe method.
+//
+// Example:
+//	s.AddN("str", 3)
+func (s *ThreadUnsafeMultiSet) AddN(val interface{}, n uint) {
+	if n == 0 {
+		return
+	}
+	s.set[val] += n
+	s.size += n
+}
+
+// Append adds a single occurrence of every given value, so the repeated
+// values are counted. It is not a thread-safe method.
+//
+// Example:
+//	s.Append(1, 1, 2)
+func (s *ThreadUnsafeMultiSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.AddN(val, 1)
+	}
+}
+
+// Remove deletes a single occurrence of val. It is not a thread-safe method.
+//
+// Example:
+//	s.Remove("str")
+func (s *ThreadUnsafeMultiSet) Remove(val interface{}) {
+	n, ok := s.set[val]
+	if !ok {
+		return
+	}
+	if n == 1 {
+		delete(s.set, val)
+	} else {
+		s.set[val] = n - 1
+	}
+	s.size--
+}
+
+// RemoveAll deletes all occurrences of val. It is not a thread-safe method.
+//
+// Example:
+//	s.RemoveAll("str")
+func (s *ThreadUnsafeMultiSet) RemoveAll(val interface{}) {
+	s.size -= s.set[val]
+	delete(s.set, val)
+}
+
+// Contains checks whether val occurs at least once. It is not a thread-safe
+// method.
+//
+// Example:
+//	exist := s.Contains("str")
+func (s *ThreadUnsafeMultiSet) Contains(val interface{}) bool {
+	_, ok := s.set[val]
+	return ok
+}
+
+// Count returns the number of the occurrences of val. It is not a thread-safe
+// method.
+//
+// Example:
+//	n := s.Count("str")
+func (s *ThreadUnsafeMultiSet) Count(val interface{}) uint {
+	return s.set[val]
+}
+
+// Size returns the number of all occurrences in the multiset. It is not a
+// thread-safe method.
+//
+// Example:
+//	size := s.Size()
+func (s *ThreadUnsafeMultiSet) Size() uint {
+	return s.size
+}
+
+// Clear removes all values from the multiset. It is not a thread-safe method.
+//
+// Example:
+//	s.Clear()
+func (s *ThreadUnsafeMultiSet) Clear() {
+	clear(s.set)
+	s.size = 0
+}
+
+// Empty checks whether the multiset is empty. It is not a thread-safe method.
+//
+// Example:
+//	empty := s.Empty()
+func (s *ThreadUnsafeMultiSet) Empty() bool {
+	return s.size == 0
+}
+
+// Distinct returns a new *ThreadUnsafeSet which contains the values of the
+// multiset without their counts. It is not a thread-safe method.
+//
+// Example:
+//	distinct := s.Distinct()
+func (s *ThreadUnsafeMultiSet) Distinct() Set {
+	d := newThreadUnsafeSet()
+	for val := range s.set {
+		d.Add(val)
+	}
+	return d
+}
+
+// Each calls fn for every value with its count until fn returns false. It is
+// not a thread-safe method.
+//
+// Example:
+//	s.Each(func(val interface{}, count uint) bool {
+//		fmt.Println(val, count)
+//		return true
+//	})
+func (s *ThreadUnsafeMultiSet) Each(fn func(val interface{}, count uint) bool) {
+	for val, n := range s.set {
+		if !fn(val, n) {
+			return
+		}
+	}
+}
+
+// MostCommon returns at most k values with the largest counts in descending
+// order of their counts. The values with the same count are sorted by Compare.
+// It is not a thread-safe method.
+//
+// Example:
+//	top := s.MostCommon(3)
+func (s *ThreadUnsafeMultiSet) MostCommon(k uint) []MultiSetEntry {
+	return mostCommon(s.set, k)
+}
+
+// countsOf returns the counts of the given multiset. The map of a
+// *ThreadUnsafeMultiSet is used directly, so it must not be modified.
+func countsOf(set MultiSet) map[interface{}]uint {
+	if o, ok := set.(*ThreadUnsafeMultiSet); ok {
+		return o.set
+	}
+	return toCounts(set)
+}
+
+// Union returns a new multiset which has every value with its larger count in
+// the two multisets. The returned multiset is always a *ThreadUnsafeMultiSet.
+// It is not a thread-safe method.
+//
+// Example:
+//	u := s.Union(other)
+func (s *ThreadUnsafeMultiSet) Union(set MultiSet) MultiSet {
+	u := newThreadUnsafeMultiSet()
+	u.size = unionCounts(u.set, s.set, countsOf(set))
+	return u
+}
+
+// Sum returns a new multiset which has every value with the sum of its counts
+// in the two multisets. The returned multiset is always a
+// *ThreadUnsafeMultiSet. It is not a thread-safe method.
+//
+// Example:
+//	sum := s.Sum(other)
+func (s *ThreadUnsafeMultiSet) Sum(set MultiSet) MultiSet {
+	u := newThreadUnsafeMultiSet()
+	u.size = sumCounts(u.set, s.set, countsOf(set))
+	return u
+}
+
+// Intersection returns a new multiset which has every common value with its
+// smaller count in the two multisets. The returned multiset is always a
+// *ThreadUnsafeMultiSet. It is not a thread-safe method.
+//
+// Example:
+//	inter := s.Intersection(other)
+func (s *ThreadUnsafeMultiSet) Intersection(set MultiSet) MultiSet {
+	u := newThreadUnsafeMultiSet()
+	u.size = intersectionCounts(u.set, s.set, countsOf(set))
+	return u
+}
+
+// Difference returns a new multiset which has the counts of the receiver
+// minus the counts of the given multiset. The values whose counts drop to zero
+// are not kept. The returned multiset is always a *ThreadUnsafeMultiSet. It is
+// not a thread-safe method.
+//
+// Example:
+//	diff := s.Difference(other)
+func (s *ThreadUnsafeMultiSet) Difference(set MultiSet) MultiSet {
+	u := newThreadUnsafeMultiSet()
+	u.size = differenceCounts(u.set, s.set, countsOf(set))
+	return u
+}
+
+// Equal checks whether both multisets have the same values with the same
+// counts. It is not a thread-safe method.
+//
+// Example:
+//	equal := s.Equal(other)
+func (s *ThreadUnsafeMultiSet) Equal(set MultiSet) bool {
+	return equalCounts(s.set, countsOf(set))
+}
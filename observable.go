@@ -0,0 +1,213 @@
+package set
+
+import (
+	"slices"
+	"sync"
+)
+
+// changeReporter is implemented by the sets which can report their actual
+// changes atomically. ObservableSet uses it instead of its own lock, so the
+// events are delivered after the lock of the set is released.
+type changeReporter interface {
+	addNew(values ...interface{}) []interface{}
+	removeExisting(val interface{}) bool
+	clearValues() []interface{}
+}
+
+// ObservableSet wraps a Set and notifies the subscribers about the changes of
+// its values. OnAdd handlers are called for the values which did not exist
+// before, OnRemove handlers for the values which are removed by Remove, Pop or
+// TryPop, and OnClear handlers with the values which are removed by Clear.
+// Adding an existing value or removing a missing value does not notify.
+//
+// The handlers are called synchronously in the goroutine which changes the set,
+// after the change is done and the locks are released, so they can use the
+// set. The events of the concurrent changes may be delivered in any order.
+//
+// The changes are only observed if they are done through the wrapper. The
+// methods which do not change the set, such as Contains, Each or Union, are
+// forwarded to the wrapped set, so their results have the kind of the wrapped
+// set. ObservableSet is thread-safe if the wrapped set is thread-safe.
+type ObservableSet struct {
+	Set
+	mu       sync.Mutex
+	onAdd    subscribers[func(val interface{})]
+	onRemove subscribers[func(val interface{})]
+	onClear  subscribers[func(values []interface{})]
+}
+
+// NewObservable creates a new *ObservableSet which wraps s.
+//
+//	s := set.NewObservable(set.New(set.ThreadSafe))
+//	unsubscribe := s.OnAdd(func(val interface{}) { index.Add(val) })
+//	defer unsubscribe()
+func NewObservable(s Set) *ObservableSet {
+	return &ObservableSet{Set: s}
+}
+
+// subscribers is a list of the handlers of an event. The list is replaced on
+// every change, so it can be read without the lock while dispatching.
+type subscribers[F any] struct {
+	mu   sync.Mutex
+	next uint64
+	list []subscriber[F]
+}
+
+// subscriber is a handler with its subscription id.
+type subscriber[F any] struct {
+	id uint64
+	fn F
+}
+
+// subscribe adds fn into the list and returns the function which removes it.
+func (s *subscribers[F]) subscribe(fn F) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	id := s.next
+	s.list = append(slices.Clip(s.list), subscriber[F]{id: id, fn: fn})
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.list = slices.DeleteFunc(slices.Clone(s.list), func(sub subscriber[F]) bool {
+			return sub.id == id
+		})
+	}
+}
+
+// handlers returns the current handlers in the subscription order.
+func (s *subscribers[F]) handlers() []subscriber[F] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list
+}
+
+// OnAdd subscribes fn to the values which are added into the set. It returns
+// the function which unsubscribes fn.
+//
+// Example:
+//	unsubscribe := s.OnAdd(func(val interface{}) { fmt.Println("added", val) })
+func (s *ObservableSet) OnAdd(fn func(val interface{})) func() {
+	return s.onAdd.subscribe(fn)
+}
+
+// OnRemove subscribes fn to the values which are removed from the set. It
+// returns the function which unsubscribes fn.
+//
+// Example:
+//	unsubscribe := s.OnRemove(func(val interface{}) { fmt.Println("removed", val) })
+func (s *ObservableSet) OnRemove(fn func(val interface{})) func() {
+	return s.onRemove.subscribe(fn)
+}
+
+// OnClear subscribes fn to the Clear calls which remove at least one value. fn
+// is called with the removed values, which must not be modified. It returns
+// the function which unsubscribes fn.
+//
+// Example:
+//	unsubscribe := s.OnClear(func(values []interface{}) { fmt.Println("cleared", values) })
+func (s *ObservableSet) OnClear(fn func(values []interface{})) func() {
+	return s.onClear.subscribe(fn)
+}
+
+// notifyAdd calls the OnAdd handlers for every value.
+func (s *ObservableSet) notifyAdd(values []interface{}) {
+	if len(values) == 0 {
+		return
+	}
+	for _, sub := range s.onAdd.handlers() {
+		for _, val := range values {
+			sub.fn(val)
+		}
+	}
+}
+
+// notifyRemove calls the OnRemove handlers for val.
+func (s *ObservableSet) notifyRemove(val interface{}) {
+	for _, sub := range s.onRemove.handlers() {
+		sub.fn(val)
+	}
+}
+
+// Add adds a new value to the wrapped set and notifies the OnAdd handlers if
+// the value did not exist.
+func (s *ObservableSet) Add(val interface{}) {
+	s.Append(val)
+}
+
+// Append adds multiple values into the wrapped set and notifies the OnAdd
+// handlers for the values which did not exist.
+func (s *ObservableSet) Append(values ...interface{}) {
+	var added []interface{}
+	if r, ok := s.Set.(changeReporter); ok {
+		added = r.addNew(values...)
+	} else {
+		s.mu.Lock()
+		for _, val := range values {
+			if !s.Set.Contains(val) {
+				s.Set.Add(val)
+				added = append(added, val)
+			}
+		}
+		s.mu.Unlock()
+	}
+	s.notifyAdd(added)
+}
+
+// Remove deletes the given value from the wrapped set and notifies the
+// OnRemove handlers if the value existed.
+func (s *ObservableSet) Remove(val interface{}) {
+	var removed bool
+	if r, ok := s.Set.(changeReporter); ok {
+		removed = r.removeExisting(val)
+	} else {
+		s.mu.Lock()
+		if removed = s.Set.Contains(val); removed {
+			s.Set.Remove(val)
+		}
+		s.mu.Unlock()
+	}
+	if removed {
+		s.notifyRemove(val)
+	}
+}
+
+// Pop removes a value from the wrapped set, notifies the OnRemove handlers and
+// returns the value. If there is no element in set, it returns nil.
+func (s *ObservableSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a value from the wrapped set, notifies the OnRemove handlers
+// and returns the value with true. If there is no element in set, it returns
+// nil and false.
+func (s *ObservableSet) TryPop() (interface{}, bool) {
+	s.mu.Lock()
+	val, ok := s.Set.TryPop()
+	s.mu.Unlock()
+	if ok {
+		s.notifyRemove(val)
+	}
+	return val, ok
+}
+
+// Clear removes everything from the wrapped set and notifies the OnClear
+// handlers with the removed values if the set was not empty.
+func (s *ObservableSet) Clear() {
+	var values []interface{}
+	if r, ok := s.Set.(changeReporter); ok {
+		values = r.clearValues()
+	} else {
+		s.mu.Lock()
+		values = s.Set.Slice()
+		s.Set.Clear()
+		s.mu.Unlock()
+	}
+	if len(values) == 0 {
+		return
+	}
+	for _, sub := range s.onClear.handlers() {
+		sub.fn(values)
+	}
+}
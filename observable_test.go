@@ -0,0 +1,154 @@
+package set
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// eventLog records the events of an ObservableSet.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) record(kind string) func(val interface{}) {
+	return func(val interface{}) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.events = append(l.events, kind+":"+val.(string))
+	}
+}
+
+func (l *eventLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+func TestObservableSet_Events(t *testing.T) {
+	testCases := []struct {
+		name string
+		set  Set
+	}{
+		{name: "ThreadSafe", set: New(ThreadSafe)},
+		{name: "ThreadUnsafe", set: New(ThreadUnsafe)},
+		{name: "Ordered", set: New(Ordered)},
+		{name: "Sharded", set: New(Sharded)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewObservable(tc.set)
+			log := &eventLog{}
+			s.OnAdd(log.record("add"))
+			s.OnRemove(log.record("remove"))
+			var cleared []interface{}
+			s.OnClear(func(values []interface{}) {
+				cleared = append(cleared, values...)
+			})
+
+			s.Add("a")
+			s.Add("a")
+			s.Append("b", "a", "c")
+			s.Remove("b")
+			s.Remove("missing")
+			exp := []string{"add:a", "add:b", "add:c", "remove:b"}
+			if events := log.get(); !reflect.DeepEqual(events, exp) {
+				t.Errorf("expected %v, actual %v", exp, events)
+			}
+
+			val := s.Pop()
+			if events := log.get(); events[len(events)-1] != "remove:"+val.(string) {
+				t.Errorf("Pop is not reported: %v", events)
+			}
+			s.Add("d")
+			s.Clear()
+			s.Clear()
+			sort.Slice(cleared, func(i, j int) bool { return cleared[i].(string) < cleared[j].(string) })
+			if len(cleared) != 2 || cleared[1] != "d" {
+				t.Errorf("unexpected cleared values %v", cleared)
+			}
+			if !tc.set.Empty() {
+				t.Errorf("wrapped set is not cleared")
+			}
+		})
+	}
+}
+
+func TestObservableSet_Unsubscribe(t *testing.T) {
+	s := NewObservable(New(ThreadUnsafe))
+	first, second := &eventLog{}, &eventLog{}
+	unsubscribe := s.OnAdd(first.record("first"))
+	s.OnAdd(second.record("second"))
+
+	s.Add("a")
+	unsubscribe()
+	unsubscribe()
+	s.Add("b")
+
+	if events := first.get(); !reflect.DeepEqual(events, []string{"first:a"}) {
+		t.Errorf("unexpected events of the unsubscribed handler %v", events)
+	}
+	if events := second.get(); !reflect.DeepEqual(events, []string{"second:a", "second:b"}) {
+		t.Errorf("unexpected events of the subscribed handler %v", events)
+	}
+}
+
+func TestObservableSet_HandlerUsesSet(t *testing.T) {
+	inner := New(ThreadSafe)
+	s := NewObservable(inner)
+	// The handler runs after the lock is released, so it can use the set.
+	s.OnAdd(func(val interface{}) {
+		if !s.Contains(val) || s.Size() == 0 {
+			t.Errorf("added value %v is not visible from the handler", val)
+		}
+		s.Remove(val)
+	})
+	var removed []interface{}
+	s.OnRemove(func(val interface{}) {
+		removed = append(removed, val)
+	})
+
+	s.Append(1, 2)
+	if !s.Empty() || len(removed) != 2 {
+		t.Errorf("expected the values to be removed by the handler, removed %v", removed)
+	}
+	if _, ok := s.Union(New(ThreadUnsafe)).(*ThreadSafeSet); !ok {
+		t.Errorf("forwarded methods must return the wrapped kind")
+	}
+}
+
+func TestObservableSet_Concurrent(t *testing.T) {
+	s := NewObservable(New(ThreadSafe))
+	var mu sync.Mutex
+	adds := map[interface{}]int{}
+	s.OnAdd(func(val interface{}) {
+		mu.Lock()
+		adds[val]++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				s.Add(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every value is reported once although it is added by every goroutine.
+	if len(adds) != 500 {
+		t.Errorf("expected 500 added values, actual %v", len(adds))
+	}
+	for val, n := range adds {
+		if n != 1 {
+			t.Errorf("%v is reported %v times", val, n)
+		}
+	}
+}
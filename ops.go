@@ -0,0 +1,113 @@
+package set
+
+// The functions in this file are the generic implementations of the binary
+// operations. They only use the methods of the Set interface, so they work with
+// any Set implementation. The result is stored into dst which must be an empty
+// set of the receiver's kind.
+
+// union adds all values of a and b into dst and returns dst.
+func union(dst, a, b Set) Set {
+	for _, s := range []Set{a, b} {
+		s.Each(func(val interface{}) bool {
+			dst.Add(val)
+			return true
+		})
+	}
+	return dst
+}
+
+// intersection adds the values of a which also exist in b into dst and returns
+// dst. The values of b are copied first, so b is not locked while a is walked.
+func intersection(dst, a, b Set) Set {
+	o := toMap(b)
+	return filter(a, dst, func(val interface{}) bool {
+		_, ok := o[val]
+		return ok
+	})
+}
+
+// difference adds the values of a which do not exist in b into dst and returns
+// dst.
+func difference(dst, a, b Set) Set {
+	o := toMap(b)
+	return filter(a, dst, func(val interface{}) bool {
+		_, ok := o[val]
+		return !ok
+	})
+}
+
+// symmetricDifference adds the values which exist in only one of a and b into
+// dst and returns dst.
+func symmetricDifference(dst, a, b Set) Set {
+	x, y := toMap(a), toMap(b)
+	for val := range x {
+		if _, ok := y[val]; !ok {
+			dst.Add(val)
+		}
+	}
+	for val := range y {
+		if _, ok := x[val]; !ok {
+			dst.Add(val)
+		}
+	}
+	return dst
+}
+
+// isSubset returns true if all values of a exist in b.
+func isSubset(a, b Set) bool {
+	o := toMap(b)
+	if a.Size() > uint(len(o)) {
+		return false
+	}
+	return allMatch(a, func(val interface{}) bool {
+		_, ok := o[val]
+		return ok
+	})
+}
+
+// isDisjoint returns true if none of the values of a exist in b.
+func isDisjoint(a, b Set) bool {
+	o := toMap(b)
+	return !anyMatch(a, func(val interface{}) bool {
+		_, ok := o[val]
+		return ok
+	})
+}
+
+// equal returns true if a and b contain exactly the same values.
+func equal(a, b Set) bool {
+	o := toMap(b)
+	if a.Size() != uint(len(o)) {
+		return false
+	}
+	return allMatch(a, func(val interface{}) bool {
+		_, ok := o[val]
+		return ok
+	})
+}
+
+// toMap copies the values of s into a new map.
+func toMap(s Set) map[interface{}]struct{} {
+	m := make(map[interface{}]struct{}, s.Size())
+	s.Each(func(val interface{}) bool {
+		m[val] = setVal
+		return true
+	})
+	return m
+}
+
+// replacer is implemented by the sets which can replace their values under a
+// single lock.
+type replacer interface {
+	replace(values []interface{})
+}
+
+// replaceValues replaces the values of s with values.
+func replaceValues(s Set, values []interface{}) {
+	if r, ok := s.(replacer); ok {
+		r.replace(values)
+		return
+	}
+	s.Clear()
+	s.Append(values...)
+}
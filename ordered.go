@@ -0,0 +1,629 @@
+package set
+
+import "iter"
+
+// OrderedSet is a set type which keeps its values sorted. It is backed by an
+// AVL tree, so Add, Remove and Contains take O(log n) time. The values are
+// compared with the Comparator given to NewOrdered, or with Compare if the set
+// is created by New(Ordered). It does not provide the thread-safety.
+//
+// The values are visited in ascending order by Slice, Each and All. The set
+// must not be modified during the iteration.
+type OrderedSet struct {
+	root *orderedNode
+	cmp  Comparator
+}
+
+// orderedNode is a node of the AVL tree. size is the number of the values in
+// the subtree, and it is used by Rank and Select.
+type orderedNode struct {
+	val         interface{}
+	left, right *orderedNode
+	height      int
+	size        uint
+}
+
+// NewOrdered creates a new *OrderedSet which sorts its values with cmp. If cmp
+// is nil, Compare is used.
+//
+//	s := set.NewOrdered(func(a, b interface{}) int { return a.(int) - b.(int) })
+func NewOrdered(cmp Comparator) *OrderedSet {
+	if cmp == nil {
+		cmp = Compare
+	}
+	return &OrderedSet{cmp: cmp}
+}
+
+// newOrderedSet creates a new empty *OrderedSet which has the same comparator
+// with s.
+func (s *OrderedSet) newOrderedSet() *OrderedSet {
+	return &OrderedSet{cmp: s.cmp}
+}
+
+// Add adds a new value to set. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Add(12)
+func (s *OrderedSet) Add(val interface{}) {
+	s.root, _ = s.insert(s.root, val)
+}
+
+// Append adds multiple values into set. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	s.Append(1, 2, 3, 4)
+func (s *OrderedSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Remove(2)
+func (s *OrderedSet) Remove(val interface{}) {
+	s.root, _ = s.delete(s.root, val)
+}
+
+// Contains checks the value whether exists in the set. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	exist := s.Contains(1)
+func (s *OrderedSet) Contains(val interface{}) bool {
+	n := s.root
+	for n != nil {
+		c := s.cmp(val, n.val)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the length of the set which means that number of value of the set.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	size := s.Size()
+func (s *OrderedSet) Size() uint {
+	return s.root.sizeOf()
+}
+
+// Pop removes the smallest value from the set and returns it. If there is no
+// element in set, it returns nil. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	min := s.Pop()
+func (s *OrderedSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the smallest value from the set and returns it with true. If
+// there is no element in set, it returns nil and false. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	min, ok := s.TryPop()
+func (s *OrderedSet) TryPop() (interface{}, bool) {
+	if s.root == nil {
+		return nil, false
+	}
+	var min *orderedNode
+	s.root, min = deleteMin(s.root)
+	return min.val, true
+}
+
+// Peek returns the smallest value without removing it. If there is no element
+// in set, it returns nil. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	min := s.Peek()
+func (s *OrderedSet) Peek() interface{} {
+	val, _ := s.Min()
+	return val
+}
+
+// Clear removes everything from the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Clear()
+func (s *OrderedSet) Clear() {
+	s.root = nil
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	empty := s.Empty()
+func (s *OrderedSet) Empty() bool {
+	return s.root == nil
+}
+
+// Slice returns the elements of the set as a slice in ascending order. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	setSlice := s.Slice()
+func (s *OrderedSet) Slice() []interface{} {
+	values := make([]interface{}, 0, s.Size())
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every value in the set in ascending order until fn returns
+// false. fn must not modify the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *OrderedSet) Each(fn func(val interface{}) bool) {
+	s.root.walk(fn)
+}
+
+// All returns an iterator over the values of the set in ascending order. The
+// loop body must not modify the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *OrderedSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Min returns the smallest value in the set with true. If there is no element
+// in set, it returns nil and false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	min, ok := s.Min()
+func (s *OrderedSet) Min() (interface{}, bool) {
+	n := s.root
+	if n == nil {
+		return nil, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.val, true
+}
+
+// Max returns the greatest value in the set with true. If there is no element
+// in set, it returns nil and false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	max, ok := s.Max()
+func (s *OrderedSet) Max() (interface{}, bool) {
+	n := s.root
+	if n == nil {
+		return nil, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.val, true
+}
+
+// Floor returns the greatest value which is less than or equal to val with
+// true. If there is no such value, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	floor, ok := s.Floor(10)
+func (s *OrderedSet) Floor(val interface{}) (interface{}, bool) {
+	var found *orderedNode
+	for n := s.root; n != nil; {
+		c := s.cmp(val, n.val)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			found = n
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found.val, true
+}
+
+// Ceiling returns the smallest value which is greater than or equal to val with
+// true. If there is no such value, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	ceiling, ok := s.Ceiling(10)
+func (s *OrderedSet) Ceiling(val interface{}) (interface{}, bool) {
+	var found *orderedNode
+	for n := s.root; n != nil; {
+		c := s.cmp(val, n.val)
+		switch {
+		case c < 0:
+			found = n
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return found.val, true
+}
+
+// Range returns the values which are greater than or equal to lo and less than
+// or equal to hi in ascending order. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	values := s.Range(10, 20)
+func (s *OrderedSet) Range(lo, hi interface{}) []interface{} {
+	var values []interface{}
+	s.walkRange(s.root, lo, hi, func(val interface{}) {
+		values = append(values, val)
+	})
+	return values
+}
+
+// walkRange calls fn for the values in n which are in [lo, hi] in ascending
+// order. The subtrees out of the range are skipped.
+func (s *OrderedSet) walkRange(n *orderedNode, lo, hi interface{}, fn func(val interface{})) {
+	if n == nil {
+		return
+	}
+	cl, ch := s.cmp(n.val, lo), s.cmp(n.val, hi)
+	if cl > 0 {
+		s.walkRange(n.left, lo, hi, fn)
+	}
+	if cl >= 0 && ch <= 0 {
+		fn(n.val)
+	}
+	if ch < 0 {
+		s.walkRange(n.right, lo, hi, fn)
+	}
+}
+
+// Rank returns the number of values in the set which are less than val. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	rank := s.Rank(10)
+func (s *OrderedSet) Rank(val interface{}) uint {
+	var rank uint
+	for n := s.root; n != nil; {
+		c := s.cmp(val, n.val)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.sizeOf() + 1
+			n = n.right
+		default:
+			return rank + n.left.sizeOf()
+		}
+	}
+	return rank
+}
+
+// Select returns the k-th smallest value in the set with true. k starts from
+// zero. If k is out of the range, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	median, ok := s.Select(s.Size() / 2)
+func (s *OrderedSet) Select(k uint) (interface{}, bool) {
+	for n := s.root; n != nil; {
+		l := n.left.sizeOf()
+		switch {
+		case k < l:
+			n = n.left
+		case k > l:
+			k -= l + 1
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+	return nil, false
+}
+
+// Filter returns a new set that contains the values which satisfy pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *OrderedSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, s.newOrderedSet(), pred)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. The results are sorted with the comparator of s. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	doubles := s.Map(func(val interface{}) interface{} { return val.(int) * 2 })
+func (s *OrderedSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, s.newOrderedSet(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value in ascending order, starting with init. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+func (s *OrderedSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the
+// values which satisfy pred and the second one contains the others. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *OrderedSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, s.newOrderedSet(), s.newOrderedSet(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	hasNegative := s.AnyMatch(func(val interface{}) bool { return val.(int) < 0 })
+func (s *OrderedSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	allPositive := s.AllMatch(func(val interface{}) bool { return val.(int) > 0 })
+func (s *OrderedSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evenCount := s.CountIf(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *OrderedSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *OrderedSet that contains all items from the receiver set
+// and all items from the given set. The given set can be any Set
+// implementation. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *OrderedSet) Union(set Set) Set {
+	return union(s.newOrderedSet(), s, set)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *OrderedSet that stores the common ones. The given set can be any Set
+// implementation. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *OrderedSet) Intersection(set Set) Set {
+	return intersection(s.newOrderedSet(), s, set)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *OrderedSet. The given set can be any Set implementation. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *OrderedSet) Difference(set Set) Set {
+	return difference(s.newOrderedSet(), s, set)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *OrderedSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *OrderedSet) IsSuperset(set Set) bool {
+	if s.Size() < set.Size() {
+		return false
+	}
+	return set.AllMatch(s.Contains)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *OrderedSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *OrderedSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *OrderedSet that contains from two sets,
+// but not the items are present in both sets. It is not a thread-safe method.
+// It does not handle the concurrency.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *OrderedSet) SymmetricDifference(set Set) Set {
+	return symmetricDifference(s.newOrderedSet(), s, set)
+}
+
+// insert adds val into the subtree n and returns the new root of the subtree.
+// It returns false if val already exists.
+func (s *OrderedSet) insert(n *orderedNode, val interface{}) (*orderedNode, bool) {
+	if n == nil {
+		return &orderedNode{val: val, height: 1, size: 1}, true
+	}
+	var added bool
+	c := s.cmp(val, n.val)
+	switch {
+	case c < 0:
+		n.left, added = s.insert(n.left, val)
+	case c > 0:
+		n.right, added = s.insert(n.right, val)
+	default:
+		return n, false
+	}
+	return n.balance(), added
+}
+
+// delete removes val from the subtree n and returns the new root of the
+// subtree. It returns false if val does not exist.
+func (s *OrderedSet) delete(n *orderedNode, val interface{}) (*orderedNode, bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	c := s.cmp(val, n.val)
+	switch {
+	case c < 0:
+		n.left, removed = s.delete(n.left, val)
+	case c > 0:
+		n.right, removed = s.delete(n.right, val)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		var min *orderedNode
+		n.right, min = deleteMin(n.right)
+		min.left, min.right = n.left, n.right
+		return min.balance(), true
+	}
+	return n.balance(), removed
+}
+
+// deleteMin removes the smallest node from the subtree n. It returns the new
+// root of the subtree and the removed node.
+func deleteMin(n *orderedNode) (*orderedNode, *orderedNode) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var min *orderedNode
+	n.left, min = deleteMin(n.left)
+	return n.balance(), min
+}
+
+// walk calls fn for every value in the subtree n in ascending order until fn
+// returns false. It returns false if the walk is stopped.
+func (n *orderedNode) walk(fn func(val interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(fn) && fn(n.val) && n.right.walk(fn)
+}
+
+func (n *orderedNode) heightOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *orderedNode) sizeOf() uint {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recalculates the height and the size of n from its children.
+func (n *orderedNode) update() {
+	n.height = 1 + max(n.left.heightOf(), n.right.heightOf())
+	n.size = 1 + n.left.sizeOf() + n.right.sizeOf()
+}
+
+func (n *orderedNode) rotateLeft() *orderedNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *orderedNode) rotateRight() *orderedNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// balance restores the AVL property of n after an insertion or a deletion in
+// its subtrees and returns the new root of the subtree.
+func (n *orderedNode) balance() *orderedNode {
+	n.update()
+	switch bf := n.left.heightOf() - n.right.heightOf(); {
+	case bf > 1:
+		if n.left.left.heightOf() < n.left.right.heightOf() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.right.heightOf() < n.right.left.heightOf() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
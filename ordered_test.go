@@ -0,0 +1,222 @@
+package set
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNewOrdered(t *testing.T) {
+	if _, ok := New(Ordered).(*OrderedSet); !ok {
+		t.Errorf("expected *OrderedSet")
+	}
+}
+
+func TestOrderedSet_AddRemove(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		removes  []interface{}
+		expSlice []interface{}
+	}{
+		{
+			name: "Empty set",
+		},
+		{
+			name:     "Sorted ints",
+			values:   []interface{}{5, 3, 8, 1, 4, 3},
+			expSlice: []interface{}{1, 3, 4, 5, 8},
+		},
+		{
+			name:     "Remove values",
+			values:   []interface{}{5, 3, 8, 1, 4},
+			removes:  []interface{}{3, 100, 5},
+			expSlice: []interface{}{1, 4, 8},
+		},
+		{
+			name:     "Mixed types",
+			values:   []interface{}{"b", 2, true, "a", 1.5, nil, false},
+			expSlice: []interface{}{nil, false, true, 1.5, 2, "a", "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewOrdered(nil)
+			s.Append(tc.values...)
+			for _, val := range tc.removes {
+				s.Remove(val)
+			}
+
+			values := s.Slice()
+			if len(values) != len(tc.expSlice) || (len(values) > 0 && !reflect.DeepEqual(values, tc.expSlice)) {
+				t.Errorf("expected %v, actual %v", tc.expSlice, values)
+			}
+			if s.Size() != uint(len(tc.expSlice)) {
+				t.Errorf("expected size %v, actual size %v", len(tc.expSlice), s.Size())
+			}
+			for _, val := range tc.expSlice {
+				if !s.Contains(val) {
+					t.Errorf("%v not exist in set", val)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderedSet_Comparator(t *testing.T) {
+	s := NewOrdered(func(a, b interface{}) int {
+		return b.(int) - a.(int)
+	})
+	s.Append(1, 3, 2)
+	if values := s.Slice(); !reflect.DeepEqual(values, []interface{}{3, 2, 1}) {
+		t.Errorf("expected descending order, actual %v", values)
+	}
+	if union := s.Union(New(ThreadUnsafe)).(*OrderedSet); !reflect.DeepEqual(union.Slice(), []interface{}{3, 2, 1}) {
+		t.Errorf("expected comparator is kept, actual %v", union.Slice())
+	}
+}
+
+func TestOrderedSet_Pop(t *testing.T) {
+	s := NewOrdered(nil)
+	if val, ok := s.TryPop(); ok || val != nil {
+		t.Errorf("expected nil and false, actual %v and %v", val, ok)
+	}
+	s.Append(3, 1, 2)
+	if val := s.Peek(); val != 1 {
+		t.Errorf("expected 1, actual %v", val)
+	}
+	for _, exp := range []interface{}{1, 2, 3} {
+		if val := s.Pop(); val != exp {
+			t.Errorf("expected %v, actual %v", exp, val)
+		}
+	}
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
+
+func TestOrderedSet_Queries(t *testing.T) {
+	s := NewOrdered(nil)
+	s.Append(10, 20, 30, 40, 50)
+
+	testCases := []struct {
+		name   string
+		query  func() (interface{}, bool)
+		expVal interface{}
+		expOk  bool
+	}{
+		{"Min", s.Min, 10, true},
+		{"Max", s.Max, 50, true},
+		{"Floor exact", func() (interface{}, bool) { return s.Floor(30) }, 30, true},
+		{"Floor between", func() (interface{}, bool) { return s.Floor(35) }, 30, true},
+		{"Floor below min", func() (interface{}, bool) { return s.Floor(5) }, nil, false},
+		{"Ceiling exact", func() (interface{}, bool) { return s.Ceiling(30) }, 30, true},
+		{"Ceiling between", func() (interface{}, bool) { return s.Ceiling(35) }, 40, true},
+		{"Ceiling above max", func() (interface{}, bool) { return s.Ceiling(55) }, nil, false},
+		{"Select first", func() (interface{}, bool) { return s.Select(0) }, 10, true},
+		{"Select last", func() (interface{}, bool) { return s.Select(4) }, 50, true},
+		{"Select out of range", func() (interface{}, bool) { return s.Select(5) }, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			val, ok := tc.query()
+			if val != tc.expVal || ok != tc.expOk {
+				t.Errorf("expected %v and %v, actual %v and %v", tc.expVal, tc.expOk, val, ok)
+			}
+		})
+	}
+
+	if rank := s.Rank(30); rank != 2 {
+		t.Errorf("expected rank 2, actual rank %v", rank)
+	}
+	if rank := s.Rank(35); rank != 3 {
+		t.Errorf("expected rank 3, actual rank %v", rank)
+	}
+	if values := s.Range(15, 40); !reflect.DeepEqual(values, []interface{}{20, 30, 40}) {
+		t.Errorf("expected [20 30 40], actual %v", values)
+	}
+	if values := s.Range(60, 70); len(values) != 0 {
+		t.Errorf("expected empty range, actual %v", values)
+	}
+}
+
+func TestOrderedSet_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := NewOrdered(nil)
+	m := make(map[int]struct{})
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(500)
+		if r.Intn(3) == 0 {
+			s.Remove(v)
+			delete(m, v)
+		} else {
+			s.Add(v)
+			m[v] = setVal
+		}
+	}
+
+	if s.Size() != uint(len(m)) {
+		t.Fatalf("expected size %v, actual size %v", len(m), s.Size())
+	}
+	checkOrderedNode(t, s.root)
+
+	prev := -1
+	var k uint
+	s.Each(func(val interface{}) bool {
+		v := val.(int)
+		if _, ok := m[v]; !ok || v <= prev {
+			t.Fatalf("unexpected value %v after %v", v, prev)
+		}
+		if rank := s.Rank(v); rank != k {
+			t.Fatalf("expected rank %v, actual rank %v", k, rank)
+		}
+		prev = v
+		k++
+		return true
+	})
+}
+
+// checkOrderedNode checks the AVL invariants of the subtree n and returns its
+// height.
+func checkOrderedNode(t *testing.T, n *orderedNode) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	l, r := checkOrderedNode(t, n.left), checkOrderedNode(t, n.right)
+	if l-r > 1 || r-l > 1 {
+		t.Fatalf("unbalanced node %v: left height %v, right height %v", n.val, l, r)
+	}
+	if n.size != 1+n.left.sizeOf()+n.right.sizeOf() {
+		t.Fatalf("wrong size of node %v", n.val)
+	}
+	return 1 + max(l, r)
+}
+
+func TestOrderedSet_SetOperations(t *testing.T) {
+	s := NewOrdered(nil)
+	s.Append(1, 2, 3)
+	o := New(ThreadSafe)
+	o.Append(2, 3, 4)
+
+	checkSet(t, "union", s.Union(o), false, 1, 2, 3, 4)
+	checkSet(t, "intersection", s.Intersection(o), false, 2, 3)
+	checkSet(t, "difference", s.Difference(o), false, 1)
+	checkSet(t, "symmetric difference", s.SymmetricDifference(o), false, 1, 4)
+	if s.IsSubset(o) || s.IsSuperset(o) || s.IsDisjoint(o) || s.Equal(o) {
+		t.Errorf("expected overlapping sets")
+	}
+	if _, ok := s.Union(o).(*OrderedSet); !ok {
+		t.Errorf("expected *OrderedSet")
+	}
+	o.Remove(4)
+	o.Add(1)
+	if !s.Equal(o) || !s.IsSubset(o) || !s.IsSuperset(o) {
+		t.Errorf("expected equal sets")
+	}
+	if !reflect.DeepEqual(s.Filter(func(val interface{}) bool { return val.(int) > 1 }).Slice(), []interface{}{2, 3}) {
+		t.Errorf("expected sorted filter result")
+	}
+}
@@ -0,0 +1,901 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// ErrInvalidFormat is returned when the serialized data of a set is corrupted
+// or it is not in the expected format.
+var ErrInvalidFormat = errors.New("set: invalid serialized data")
+
+// RoaringBitmap is a compressed set type for the uint32 values. The values are
+// grouped by their high 16 bits and every group is stored in the smallest one
+// of a sorted array, a bitmap or a list of runs, so it is memory efficient for
+// both the sparse and the dense sets. It does not provide the thread-safety.
+//
+// The methods of the Set interface accept the values of any integer type in
+// the range of uint32, but the values are returned as uint32 from Slice, Each,
+// All and Pop. Adding a value out of the range or a value which is not an
+// integer panics. The binary operations compare the values by their integer
+// values, and the other sets are converted into a *RoaringBitmap first.
+//
+// RoaringBitmap is serialized in the Roaring portable format, so the data can
+// be read by the other Roaring implementations.
+type RoaringBitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+// NewRoaringBitmap creates a new empty *RoaringBitmap.
+//
+//	s := set.NewRoaringBitmap()
+func NewRoaringBitmap() *RoaringBitmap {
+	return &RoaringBitmap{}
+}
+
+// NewRoaringBitmapOf creates a new *RoaringBitmap which contains the given
+// values. The Go syntax of RoaringBitmap, which is printed by %#v, uses
+// NewRoaringBitmapOf.
+//
+//	s := set.NewRoaringBitmapOf(1, 5, 70000)
+func NewRoaringBitmapOf(values ...uint32) *RoaringBitmap {
+	s := NewRoaringBitmap()
+	for _, x := range values {
+		s.AddUint32(x)
+	}
+	return s
+}
+
+// AddUint32 adds x into the set.
+//
+// Example:
+//	s.AddUint32(12)
+func (s *RoaringBitmap) AddUint32(x uint32) {
+	hi, lo := uint16(x>>16), uint16(x)
+	i, found := slices.BinarySearch(s.keys, hi)
+	if found {
+		s.containers[i] = s.containers[i].add(lo)
+		return
+	}
+	s.keys = slices.Insert(s.keys, i, hi)
+	s.containers = slices.Insert(s.containers, i, container(&arrayContainer{values: []uint16{lo}}))
+}
+
+// RemoveUint32 deletes x from the set.
+//
+// Example:
+//	s.RemoveUint32(12)
+func (s *RoaringBitmap) RemoveUint32(x uint32) {
+	hi, lo := uint16(x>>16), uint16(x)
+	i, found := slices.BinarySearch(s.keys, hi)
+	if !found {
+		return
+	}
+	s.containers[i] = s.containers[i].remove(lo)
+	if s.containers[i].cardinality() == 0 {
+		s.keys = slices.Delete(s.keys, i, i+1)
+		s.containers = slices.Delete(s.containers, i, i+1)
+	}
+}
+
+// ContainsUint32 checks whether x exists in the set.
+//
+// Example:
+//	exist := s.ContainsUint32(12)
+func (s *RoaringBitmap) ContainsUint32(x uint32) bool {
+	i, found := slices.BinarySearch(s.keys, uint16(x>>16))
+	return found && s.containers[i].contains(uint16(x))
+}
+
+// Rank returns the number of the values in the set which are less than or
+// equal to x.
+//
+// Example:
+//	rank := s.Rank(1000)
+func (s *RoaringBitmap) Rank(x uint32) uint64 {
+	hi := uint16(x >> 16)
+	var rank uint64
+	for i, key := range s.keys {
+		if key > hi {
+			break
+		}
+		if key < hi {
+			rank += uint64(s.containers[i].cardinality())
+			continue
+		}
+		rank += uint64(s.containers[i].rank(uint16(x)))
+	}
+	return rank
+}
+
+// Select returns the i-th smallest value in the set with true. i starts from
+// zero. If i is out of the range, it returns false.
+//
+// Example:
+//	median, ok := s.Select(uint64(s.Size() / 2))
+func (s *RoaringBitmap) Select(i uint64) (uint32, bool) {
+	for k, c := range s.containers {
+		card := uint64(c.cardinality())
+		if i < card {
+			return uint32(s.keys[k])<<16 | uint32(c.selectAt(int(i))), true
+		}
+		i -= card
+	}
+	return 0, false
+}
+
+// RunOptimize converts every container into the smallest one of the array, the
+// bitmap and the run containers. It should be called after adding the long
+// runs of the consecutive values, especially before the serialization.
+//
+// Example:
+//	s.RunOptimize()
+func (s *RoaringBitmap) RunOptimize() {
+	for i, c := range s.containers {
+		s.containers[i] = optimize(c)
+	}
+}
+
+// Add adds a new value to set. The value must be an integer in the range of
+// uint32, otherwise Add panics. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Add(12)
+func (s *RoaringBitmap) Add(val interface{}) {
+	x, ok := toRoaringValue(val)
+	if !ok {
+		panic(fmt.Sprintf("set: RoaringBitmap only accepts uint32 values, got %v (%T)", val, val))
+	}
+	s.AddUint32(x)
+}
+
+// Append adds multiple values into set. All values must be integers in the
+// range of uint32, otherwise Append panics. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	s.Append(1, 2, 3, 4)
+func (s *RoaringBitmap) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Remove(2)
+func (s *RoaringBitmap) Remove(val interface{}) {
+	if x, ok := toRoaringValue(val); ok {
+		s.RemoveUint32(x)
+	}
+}
+
+// Contains checks the value whether exists in the set. It returns false if the
+// value is not an integer in the range of uint32. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	exist := s.Contains(1)
+func (s *RoaringBitmap) Contains(val interface{}) bool {
+	x, ok := toRoaringValue(val)
+	return ok && s.ContainsUint32(x)
+}
+
+// Size returns the length of the set which means that number of value of the set.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	size := s.Size()
+func (s *RoaringBitmap) Size() uint {
+	var size uint
+	for _, c := range s.containers {
+		size += uint(c.cardinality())
+	}
+	return size
+}
+
+// Pop removes the smallest value from the set and returns it as uint32. If
+// there is no element in set, it returns nil. It is not a thread-safe method.
+// It does not handle the concurrency.
+//
+// Example:
+//	min := s.Pop()
+func (s *RoaringBitmap) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes the smallest value from the set and returns it as uint32 with
+// true. If there is no element in set, it returns nil and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	min, ok := s.TryPop()
+func (s *RoaringBitmap) TryPop() (interface{}, bool) {
+	x, ok := s.Select(0)
+	if !ok {
+		return nil, false
+	}
+	s.RemoveUint32(x)
+	return x, true
+}
+
+// Peek returns the smallest value as uint32 without removing it. If there is
+// no element in set, it returns nil. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	min := s.Peek()
+func (s *RoaringBitmap) Peek() interface{} {
+	if x, ok := s.Select(0); ok {
+		return x
+	}
+	return nil
+}
+
+// Clear removes everything from the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Clear()
+func (s *RoaringBitmap) Clear() {
+	s.keys, s.containers = nil, nil
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	empty := s.Empty()
+func (s *RoaringBitmap) Empty() bool {
+	return len(s.keys) == 0
+}
+
+// Slice returns the elements of the set as a slice of uint32 values in
+// ascending order. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	setSlice := s.Slice()
+func (s *RoaringBitmap) Slice() []interface{} {
+	values := make([]interface{}, 0, s.Size())
+	s.Each(func(val interface{}) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Each calls fn for every value in the set in ascending order until fn returns
+// false. The values are given as uint32. fn must not modify the set. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *RoaringBitmap) Each(fn func(val interface{}) bool) {
+	for i, c := range s.containers {
+		if !c.each(uint32(s.keys[i])<<16, func(x uint32) bool { return fn(x) }) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set in ascending order. The
+// values are given as uint32. The loop body must not modify the set. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *RoaringBitmap) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new *RoaringBitmap that contains the values which satisfy
+// pred. It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(uint32)%2 == 0 })
+func (s *RoaringBitmap) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, NewRoaringBitmap(), pred)
+}
+
+// Map returns a new *RoaringBitmap that contains the results of fn for every
+// value in the set. fn must return integers in the range of uint32, otherwise
+// Map panics. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	doubles := s.Map(func(val interface{}) interface{} { return val.(uint32) * 2 })
+func (s *RoaringBitmap) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, NewRoaringBitmap(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value in ascending order, starting with init. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	sum := s.Reduce(uint64(0), func(acc, val interface{}) interface{} { return acc.(uint64) + uint64(val.(uint32)) })
+func (s *RoaringBitmap) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new *RoaringBitmaps. The first one contains
+// the values which satisfy pred and the second one contains the others. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(uint32)%2 == 0 })
+func (s *RoaringBitmap) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, NewRoaringBitmap(), NewRoaringBitmap(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	hasBig := s.AnyMatch(func(val interface{}) bool { return val.(uint32) > 1000 })
+func (s *RoaringBitmap) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	allSmall := s.AllMatch(func(val interface{}) bool { return val.(uint32) < 1000 })
+func (s *RoaringBitmap) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evenCount := s.CountIf(func(val interface{}) bool { return val.(uint32)%2 == 0 })
+func (s *RoaringBitmap) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *RoaringBitmap that contains all items from the receiver
+// set and all items from the given set. All values of the given set must be
+// integers in the range of uint32, otherwise Union panics. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *RoaringBitmap) Union(set Set) Set {
+	o := toRoaring(set)
+	r := NewRoaringBitmap()
+	i, j := 0, 0
+	for i < len(s.keys) && j < len(o.keys) {
+		switch {
+		case s.keys[i] < o.keys[j]:
+			r.appendContainer(s.keys[i], s.containers[i].clone())
+			i++
+		case s.keys[i] > o.keys[j]:
+			r.appendContainer(o.keys[j], o.containers[j].clone())
+			j++
+		default:
+			r.appendContainer(s.keys[i], orContainers(s.containers[i], o.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; i < len(s.keys); i++ {
+		r.appendContainer(s.keys[i], s.containers[i].clone())
+	}
+	for ; j < len(o.keys); j++ {
+		r.appendContainer(o.keys[j], o.containers[j].clone())
+	}
+	return r
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *RoaringBitmap that stores the common ones. The values of the given set which
+// are out of the range of uint32 are ignored. It is not a thread-safe method.
+// It does not handle the concurrency.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *RoaringBitmap) Intersection(set Set) Set {
+	o, _ := asRoaring(set)
+	r := NewRoaringBitmap()
+	s.eachPair(o, func(key uint16, a, b container) {
+		r.appendContainer(key, andContainers(a, b))
+	})
+	return r
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *RoaringBitmap. The values of the given set which are out of the range
+// of uint32 are ignored. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *RoaringBitmap) Difference(set Set) Set {
+	o, _ := asRoaring(set)
+	r := NewRoaringBitmap()
+	j := 0
+	for i, key := range s.keys {
+		for j < len(o.keys) && o.keys[j] < key {
+			j++
+		}
+		if j < len(o.keys) && o.keys[j] == key {
+			r.appendContainer(key, andNotContainers(s.containers[i], o.containers[j]))
+		} else {
+			r.appendContainer(key, s.containers[i].clone())
+		}
+	}
+	return r
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *RoaringBitmap) IsSubset(set Set) bool {
+	o, _ := asRoaring(set)
+	return s.isSubset(o)
+}
+
+// isSubset returns true if all values of s exist in o.
+func (s *RoaringBitmap) isSubset(o *RoaringBitmap) bool {
+	for i, key := range s.keys {
+		j, found := slices.BinarySearch(o.keys, key)
+		if !found || andNotContainers(s.containers[i], o.containers[j]) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *RoaringBitmap) IsSuperset(set Set) bool {
+	o, ok := asRoaring(set)
+	return ok && o.isSubset(s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *RoaringBitmap) IsDisjoint(set Set) bool {
+	o, _ := asRoaring(set)
+	disjoint := true
+	s.eachPair(o, func(key uint16, a, b container) {
+		if disjoint && andContainers(a, b) != nil {
+			disjoint = false
+		}
+	})
+	return disjoint
+}
+
+// Equal checks whether both sets contain exactly the same values. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *RoaringBitmap) Equal(set Set) bool {
+	o, ok := asRoaring(set)
+	if !ok || !slices.Equal(s.keys, o.keys) {
+		return false
+	}
+	for i, c := range s.containers {
+		if c.cardinality() != o.containers[i].cardinality() || xorContainers(c, o.containers[i]) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricDifference returns a new *RoaringBitmap that contains from two sets,
+// but not the items are present in both sets. All values of the given set must
+// be integers in the range of uint32, otherwise SymmetricDifference panics. It
+// is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *RoaringBitmap) SymmetricDifference(set Set) Set {
+	o := toRoaring(set)
+	r := NewRoaringBitmap()
+	i, j := 0, 0
+	for i < len(s.keys) && j < len(o.keys) {
+		switch {
+		case s.keys[i] < o.keys[j]:
+			r.appendContainer(s.keys[i], s.containers[i].clone())
+			i++
+		case s.keys[i] > o.keys[j]:
+			r.appendContainer(o.keys[j], o.containers[j].clone())
+			j++
+		default:
+			r.appendContainer(s.keys[i], xorContainers(s.containers[i], o.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; i < len(s.keys); i++ {
+		r.appendContainer(s.keys[i], s.containers[i].clone())
+	}
+	for ; j < len(o.keys); j++ {
+		r.appendContainer(o.keys[j], o.containers[j].clone())
+	}
+	return r
+}
+
+// eachPair calls fn for every key which exists in both s and o with their
+// containers.
+func (s *RoaringBitmap) eachPair(o *RoaringBitmap, fn func(key uint16, a, b container)) {
+	i, j := 0, 0
+	for i < len(s.keys) && j < len(o.keys) {
+		switch {
+		case s.keys[i] < o.keys[j]:
+			i++
+		case s.keys[i] > o.keys[j]:
+			j++
+		default:
+			fn(s.keys[i], s.containers[i], o.containers[j])
+			i++
+			j++
+		}
+	}
+}
+
+// appendContainer adds c to the end of s with the given key. The key must be
+// greater than the keys of s. c is skipped if it is nil.
+func (s *RoaringBitmap) appendContainer(key uint16, c container) {
+	if c == nil {
+		return
+	}
+	s.keys = append(s.keys, key)
+	s.containers = append(s.containers, c)
+}
+
+// The cookies and the threshold of the Roaring portable format.
+const (
+	roaringCookieNoRun     = 12346
+	roaringCookie          = 12347
+	roaringNoOffsetMaxSize = 4
+)
+
+// MarshalBinary encodes the set in the Roaring portable format. The run
+// containers are kept as they are, so RunOptimize can be called before for the
+// smaller output.
+//
+// Example:
+//	data, err := s.MarshalBinary()
+func (s *RoaringBitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the data which is in the Roaring portable format and
+// replaces the values of the set. It returns an error which wraps
+// ErrInvalidFormat if the data is not valid.
+//
+// Example:
+//	err := s.UnmarshalBinary(data)
+func (s *RoaringBitmap) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := s.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidFormat, r.Len())
+	}
+	return nil
+}
+
+// WriteTo writes the set into w in the Roaring portable format. It returns the
+// number of the written bytes.
+//
+// Example:
+//	n, err := s.WriteTo(file)
+func (s *RoaringBitmap) WriteTo(w io.Writer) (int64, error) {
+	n := len(s.keys)
+	hasRun := slices.ContainsFunc(s.containers, func(c container) bool {
+		_, ok := c.(*runContainer)
+		return ok
+	})
+
+	var header []byte
+	if hasRun {
+		header = binary.LittleEndian.AppendUint32(header, roaringCookie|uint32(n-1)<<16)
+		flags := make([]byte, (n+7)/8)
+		for i, c := range s.containers {
+			if _, ok := c.(*runContainer); ok {
+				flags[i/8] |= 1 << (i % 8)
+			}
+		}
+		header = append(header, flags...)
+	} else {
+		header = binary.LittleEndian.AppendUint32(header, roaringCookieNoRun)
+		header = binary.LittleEndian.AppendUint32(header, uint32(n))
+	}
+	for i, c := range s.containers {
+		header = binary.LittleEndian.AppendUint16(header, s.keys[i])
+		header = binary.LittleEndian.AppendUint16(header, uint16(c.cardinality()-1))
+	}
+	if !hasRun || n >= roaringNoOffsetMaxSize {
+		offset := len(header) + 4*n
+		for _, c := range s.containers {
+			header = binary.LittleEndian.AppendUint32(header, uint32(offset))
+			offset += serializedSize(c)
+		}
+	}
+
+	written, err := w.Write(header)
+	total := int64(written)
+	if err != nil {
+		return total, err
+	}
+	for _, c := range s.containers {
+		written, err = w.Write(appendContainer(nil, c))
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadFrom reads a set in the Roaring portable format from r and replaces the
+// values of the set. It returns the number of the read bytes. It returns an
+// error which wraps ErrInvalidFormat if the data is not valid.
+//
+// Example:
+//	n, err := s.ReadFrom(file)
+func (s *RoaringBitmap) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	cookie, err := cr.uint32()
+	if err != nil {
+		return cr.n, err
+	}
+
+	var n int
+	var runFlags []byte
+	switch {
+	case cookie == roaringCookieNoRun:
+		size, err := cr.uint32()
+		if err != nil {
+			return cr.n, err
+		}
+		if size > 1<<16 {
+			return cr.n, fmt.Errorf("%w: %d containers", ErrInvalidFormat, size)
+		}
+		n = int(size)
+	case cookie&0xFFFF == roaringCookie:
+		n = int(cookie>>16) + 1
+		runFlags = make([]byte, (n+7)/8)
+		if err := cr.read(runFlags); err != nil {
+			return cr.n, err
+		}
+	default:
+		return cr.n, fmt.Errorf("%w: unknown cookie %d", ErrInvalidFormat, cookie)
+	}
+
+	keys := make([]uint16, n)
+	cards := make([]int, n)
+	for i := range keys {
+		if keys[i], err = cr.uint16(); err != nil {
+			return cr.n, err
+		}
+		card, err := cr.uint16()
+		if err != nil {
+			return cr.n, err
+		}
+		cards[i] = int(card) + 1
+		if i > 0 && keys[i] <= keys[i-1] {
+			return cr.n, fmt.Errorf("%w: keys are not sorted", ErrInvalidFormat)
+		}
+	}
+	if runFlags == nil || n >= roaringNoOffsetMaxSize {
+		// The offsets are not needed, since the containers are read in order.
+		if err := cr.read(make([]byte, 4*n)); err != nil {
+			return cr.n, err
+		}
+	}
+
+	containers := make([]container, n)
+	for i := range containers {
+		isRun := runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+		if containers[i], err = readContainer(cr, cards[i], isRun); err != nil {
+			return cr.n, err
+		}
+	}
+	s.keys, s.containers = keys, containers
+	return cr.n, nil
+}
+
+// serializedSize returns the number of the bytes of c in the Roaring portable
+// format.
+func serializedSize(c container) int {
+	if r, ok := c.(*runContainer); ok {
+		return 2 + 4*len(r.runs)
+	}
+	if c.cardinality() <= arrayMaxSize {
+		return 2 * c.cardinality()
+	}
+	return bitmapSize
+}
+
+// appendContainer appends c to b in the Roaring portable format. The run
+// containers are written as runs, and the other containers are written as an
+// array or a bitmap regarding their cardinality.
+func appendContainer(b []byte, c container) []byte {
+	if r, ok := c.(*runContainer); ok {
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(r.runs)))
+		for _, run := range r.runs {
+			b = binary.LittleEndian.AppendUint16(b, run.start)
+			b = binary.LittleEndian.AppendUint16(b, run.last-run.start)
+		}
+		return b
+	}
+	if c.cardinality() <= arrayMaxSize {
+		for _, x := range toArray(c).values {
+			b = binary.LittleEndian.AppendUint16(b, x)
+		}
+		return b
+	}
+	for _, word := range c.toBitmap().words {
+		b = binary.LittleEndian.AppendUint64(b, word)
+	}
+	return b
+}
+
+// readContainer reads a container which has card values from r.
+func readContainer(r *countingReader, card int, isRun bool) (container, error) {
+	switch {
+	case isRun:
+		numRuns, err := r.uint16()
+		if err != nil {
+			return nil, err
+		}
+		c := &runContainer{runs: make([]interval, numRuns)}
+		for i := range c.runs {
+			start, err := r.uint16()
+			if err != nil {
+				return nil, err
+			}
+			length, err := r.uint16()
+			if err != nil {
+				return nil, err
+			}
+			if uint32(start)+uint32(length) > math.MaxUint16 || (i > 0 && uint32(start) <= uint32(c.runs[i-1].last)+1) {
+				return nil, fmt.Errorf("%w: invalid run", ErrInvalidFormat)
+			}
+			c.runs[i] = interval{start: start, last: start + length}
+		}
+		if c.cardinality() != card {
+			return nil, fmt.Errorf("%w: cardinality mismatch", ErrInvalidFormat)
+		}
+		return c, nil
+	case card <= arrayMaxSize:
+		c := &arrayContainer{values: make([]uint16, card)}
+		for i := range c.values {
+			x, err := r.uint16()
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && x <= c.values[i-1] {
+				return nil, fmt.Errorf("%w: array is not sorted", ErrInvalidFormat)
+			}
+			c.values[i] = x
+		}
+		return c, nil
+	default:
+		c := newBitmapContainer()
+		buf := make([]byte, bitmapSize)
+		if err := r.read(buf); err != nil {
+			return nil, err
+		}
+		for i := range c.words {
+			c.words[i] = binary.LittleEndian.Uint64(buf[8*i:])
+			c.card += bits.OnesCount64(c.words[i])
+		}
+		if c.card != card {
+			return nil, fmt.Errorf("%w: cardinality mismatch", ErrInvalidFormat)
+		}
+		return c, nil
+	}
+}
+
+// countingReader reads the little endian integers and counts the read bytes.
+// An unexpected end of the data is reported as ErrInvalidFormat.
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	buf [8]byte
+}
+
+func (r *countingReader) read(b []byte) error {
+	n, err := io.ReadFull(r.r, b)
+	r.n += int64(n)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+func (r *countingReader) uint16() (uint16, error) {
+	err := r.read(r.buf[:2])
+	return binary.LittleEndian.Uint16(r.buf[:2]), err
+}
+
+func (r *countingReader) uint32() (uint32, error) {
+	err := r.read(r.buf[:4])
+	return binary.LittleEndian.Uint32(r.buf[:4]), err
+}
+
+// toRoaring returns s if it is a *RoaringBitmap. Otherwise, it copies the
+// values of s into a new *RoaringBitmap. It panics if any value of s is out of
+// the range of uint32.
+func toRoaring(s Set) *RoaringBitmap {
+	if r, ok := s.(*RoaringBitmap); ok {
+		return r
+	}
+	r := NewRoaringBitmap()
+	s.Each(func(val interface{}) bool {
+		r.Add(val)
+		return true
+	})
+	return r
+}
+
+// asRoaring returns s if it is a *RoaringBitmap. Otherwise, it copies the
+// values of s which are in the range of uint32 into a new *RoaringBitmap. It
+// returns false if any value of s is skipped.
+func asRoaring(s Set) (*RoaringBitmap, bool) {
+	if r, ok := s.(*RoaringBitmap); ok {
+		return r, true
+	}
+	r := NewRoaringBitmap()
+	all := true
+	s.Each(func(val interface{}) bool {
+		if x, ok := toRoaringValue(val); ok {
+			r.AddUint32(x)
+		} else {
+			all = false
+		}
+		return true
+	})
+	return r, all
+}
+
+// toRoaringValue converts val into uint32 if it is an integer in the range of
+// uint32.
+func toRoaringValue(val interface{}) (uint32, bool) {
+	i, ok := toBitIndex(val)
+	if !ok || uint64(i) > math.MaxUint32 {
+		return 0, false
+	}
+	return uint32(i), true
+}
@@ -0,0 +1,260 @@
+package set
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomRoaring creates a *RoaringBitmap and the same *ThreadUnsafeSet of
+// uint32 values. The values contain sparse, dense and consecutive parts, so all
+// container types are used.
+func randomRoaring(r *rand.Rand) (*RoaringBitmap, *ThreadUnsafeSet) {
+	s, m := NewRoaringBitmap(), newThreadUnsafeSet()
+	add := func(x uint32) {
+		s.AddUint32(x)
+		m.Add(x)
+	}
+	for i := 0; i < 200; i++ {
+		add(uint32(r.Intn(1 << 20)))
+	}
+	base := uint32(r.Intn(4)) << 16
+	for i := 0; i < 5000; i++ {
+		add(base | uint32(r.Intn(1<<16)))
+	}
+	start := uint32(r.Intn(1 << 18))
+	for i := uint32(0); i < uint32(r.Intn(3000)); i++ {
+		add(start + i)
+	}
+	if r.Intn(2) == 0 {
+		s.RunOptimize()
+	}
+	return s, m
+}
+
+func TestRoaringBitmap_AddRemove(t *testing.T) {
+	s := NewRoaringBitmap()
+	s.Append(5, uint32(1<<20), int64(70000), 5)
+	if s.Size() != 3 {
+		t.Errorf("expected size 3, actual size %v", s.Size())
+	}
+	if !s.Contains(70000) || !s.Contains(uint8(5)) || s.Contains(6) || s.Contains(-1) || s.Contains("5") {
+		t.Errorf("unexpected membership")
+	}
+	if values := s.Slice(); !reflect.DeepEqual(values, []interface{}{uint32(5), uint32(70000), uint32(1 << 20)}) {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	for i := uint32(0); i < 10000; i++ {
+		s.AddUint32(i * 2)
+	}
+	if _, ok := s.containers[0].(*bitmapContainer); !ok {
+		t.Errorf("expected bitmap container, actual %T", s.containers[0])
+	}
+	for i := uint32(0); i < 10000; i++ {
+		s.RemoveUint32(i * 2)
+	}
+	if _, ok := s.containers[0].(*arrayContainer); !ok {
+		t.Errorf("expected array container, actual %T", s.containers[0])
+	}
+	s.Remove(5)
+	s.Remove(70000)
+	s.Remove(uint64(1 << 40))
+	if val := s.Pop(); val != uint32(1<<20) || !s.Empty() {
+		t.Errorf("expected the last value is popped, actual %v", val)
+	}
+	if val, ok := s.TryPop(); ok || val != nil {
+		t.Errorf("expected nil and false, actual %v and %v", val, ok)
+	}
+}
+
+func TestRoaringBitmap_InvalidValues(t *testing.T) {
+	for _, val := range []interface{}{-1, uint64(1 << 32), "1"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %v", val)
+				}
+			}()
+			NewRoaringBitmap().Add(val)
+		}()
+	}
+}
+
+func TestRoaringBitmap_RunContainer(t *testing.T) {
+	s := NewRoaringBitmap()
+	for i := uint32(100); i < 200; i++ {
+		s.AddUint32(i)
+	}
+	s.RunOptimize()
+	c, ok := s.containers[0].(*runContainer)
+	if !ok || len(c.runs) != 1 {
+		t.Fatalf("expected a single run, actual %T", s.containers[0])
+	}
+
+	s.AddUint32(99)
+	s.AddUint32(201)
+	s.AddUint32(200)
+	s.RemoveUint32(150)
+	s.RemoveUint32(100)
+	exp := []interval{{99, 99}, {101, 149}, {151, 201}}
+	if !reflect.DeepEqual(c.runs, exp) {
+		t.Errorf("expected runs %v, actual %v", exp, c.runs)
+	}
+	if s.Size() != 101 || !s.Contains(151) || s.Contains(150) {
+		t.Errorf("unexpected values in run container")
+	}
+}
+
+func TestRoaringBitmap_RankSelect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s, _ := randomRoaring(r)
+	var k uint64
+	s.Each(func(val interface{}) bool {
+		x := val.(uint32)
+		if rank := s.Rank(x); rank != k+1 {
+			t.Fatalf("expected rank %v for %v, actual %v", k+1, x, rank)
+		}
+		if sel, ok := s.Select(k); !ok || sel != x {
+			t.Fatalf("expected select %v for %v, actual %v", x, k, sel)
+		}
+		k++
+		return true
+	})
+	if _, ok := s.Select(k); ok {
+		t.Errorf("expected false for out of range select")
+	}
+}
+
+func TestRoaringBitmap_SetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		a, ma := randomRoaring(r)
+		b, mb := randomRoaring(r)
+
+		checkSameSet(t, "union", a.Union(b), ma.Union(mb))
+		checkSameSet(t, "intersection", a.Intersection(b), ma.Intersection(mb))
+		checkSameSet(t, "difference", a.Difference(b), ma.Difference(mb))
+		checkSameSet(t, "symmetric difference", a.SymmetricDifference(b), ma.SymmetricDifference(mb))
+		checkSameSet(t, "mixed intersection", a.Intersection(mb), ma.Intersection(mb))
+		if a.IsDisjoint(b) != ma.IsDisjoint(mb) {
+			t.Errorf("unexpected disjoint result")
+		}
+
+		u := a.Union(b)
+		if !a.IsSubset(u) || !u.IsSuperset(b) {
+			t.Errorf("unexpected subset result")
+		}
+		if !a.Equal(ma) || !a.Equal(a.Union(NewRoaringBitmap())) {
+			t.Errorf("expected equal sets")
+		}
+	}
+}
+
+func TestRoaringBitmap_MarshalBinary(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []uint32
+		runOpt bool
+		exp    []byte
+	}{
+		{
+			name: "Empty set",
+			exp:  []byte{0x3a, 0x30, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:   "Array container",
+			values: []uint32{1, 2, 3},
+			exp: []byte{
+				0x3a, 0x30, 0, 0, // cookie
+				1, 0, 0, 0, // number of containers
+				0, 0, 2, 0, // key and cardinality - 1
+				16, 0, 0, 0, // offset
+				1, 0, 2, 0, 3, 0, // values
+			},
+		},
+		{
+			name:   "Run container",
+			values: []uint32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			runOpt: true,
+			exp: []byte{
+				0x3b, 0x30, 0, 0, // cookie and number of containers - 1
+				1,          // run flags
+				0, 0, 9, 0, // key and cardinality - 1
+				1, 0, // number of runs
+				1, 0, 9, 0, // start and length - 1
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewRoaringBitmap()
+			for _, x := range tc.values {
+				s.AddUint32(x)
+			}
+			if tc.runOpt {
+				s.RunOptimize()
+			}
+
+			data, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(data, tc.exp) {
+				t.Errorf("expected %v, actual %v", tc.exp, data)
+			}
+
+			d := NewRoaringBitmap()
+			if err := d.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !d.Equal(s) {
+				t.Errorf("expected %v, actual %v", s.Slice(), d.Slice())
+			}
+		})
+	}
+}
+
+func TestRoaringBitmap_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 10; i++ {
+		s, m := randomRoaring(r)
+		var buf bytes.Buffer
+		n, err := s.WriteTo(&buf)
+		if err != nil || n != int64(buf.Len()) {
+			t.Fatalf("unexpected write result %v, %v", n, err)
+		}
+
+		d := NewRoaringBitmap()
+		if n, err := d.ReadFrom(&buf); err != nil || buf.Len() != 0 {
+			t.Fatalf("unexpected read result %v, %v", n, err)
+		}
+		checkSameSet(t, "round trip", d, m)
+	}
+}
+
+func TestRoaringBitmap_UnmarshalInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty data", nil},
+		{"Unknown cookie", []byte{1, 2, 3, 4, 0, 0, 0, 0}},
+		{"Truncated header", []byte{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0}},
+		{"Truncated container", []byte{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 2, 0, 16, 0, 0, 0, 1, 0}},
+		{"Unsorted array", []byte{0x3a, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 16, 0, 0, 0, 2, 0, 1, 0}},
+		{"Trailing bytes", []byte{0x3a, 0x30, 0, 0, 0, 0, 0, 0, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewRoaringBitmap().UnmarshalBinary(tc.data)
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("expected ErrInvalidFormat, actual %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,499 @@
+package set
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// The containers of RoaringBitmap store the low 16 bits of the values which
+// share the same high 16 bits. An arrayContainer is used for the sparse
+// containers, a bitmapContainer for the dense ones and a runContainer for the
+// ones which consist of the consecutive values.
+
+const (
+	// arrayMaxSize is the maximum cardinality of an arrayContainer. The larger
+	// containers are stored as bitmapContainer.
+	arrayMaxSize = 4096
+
+	// bitmapWords is the number of the words of a bitmapContainer.
+	bitmapWords = 1 << 16 / wordSize
+
+	// bitmapSize is the number of the bytes of a serialized bitmapContainer.
+	bitmapSize = bitmapWords * 8
+)
+
+// container is a set of uint16 values.
+type container interface {
+	// add adds x and returns the container which must replace the receiver.
+	add(x uint16) container
+	// remove deletes x and returns the container which must replace the
+	// receiver.
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// each calls fn with high|x for every value x in ascending order until fn
+	// returns false. It returns false if the walk is stopped.
+	each(high uint32, fn func(val uint32) bool) bool
+	// rank returns the number of the values which are less than or equal to x.
+	rank(x uint16) int
+	// selectAt returns the i-th smallest value.
+	selectAt(i int) uint16
+	// numRuns returns the number of the runs of the consecutive values.
+	numRuns() int
+	toBitmap() *bitmapContainer
+	clone() container
+}
+
+// arrayContainer stores the values in a sorted slice.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) add(x uint16) container {
+	i, found := slices.BinarySearch(c.values, x)
+	if found {
+		return c
+	}
+	if len(c.values) == arrayMaxSize {
+		return c.toBitmap().add(x)
+	}
+	c.values = slices.Insert(c.values, i, x)
+	return c
+}
+
+func (c *arrayContainer) remove(x uint16) container {
+	if i, found := slices.BinarySearch(c.values, x); found {
+		c.values = slices.Delete(c.values, i, i+1)
+	}
+	return c
+}
+
+func (c *arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(c.values, x)
+	return found
+}
+
+func (c *arrayContainer) cardinality() int {
+	return len(c.values)
+}
+
+func (c *arrayContainer) each(high uint32, fn func(val uint32) bool) bool {
+	for _, x := range c.values {
+		if !fn(high | uint32(x)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *arrayContainer) rank(x uint16) int {
+	i, found := slices.BinarySearch(c.values, x)
+	if found {
+		i++
+	}
+	return i
+}
+
+func (c *arrayContainer) selectAt(i int) uint16 {
+	return c.values[i]
+}
+
+func (c *arrayContainer) numRuns() int {
+	n := 0
+	for i, x := range c.values {
+		if i == 0 || c.values[i-1]+1 != x {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *arrayContainer) toBitmap() *bitmapContainer {
+	b := newBitmapContainer()
+	for _, x := range c.values {
+		b.words[x/wordSize] |= 1 << (x % wordSize)
+	}
+	b.card = len(c.values)
+	return b
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{values: slices.Clone(c.values)}
+}
+
+// bitmapContainer stores every value as a bit.
+type bitmapContainer struct {
+	words []uint64
+	card  int
+}
+
+func newBitmapContainer() *bitmapContainer {
+	return &bitmapContainer{words: make([]uint64, bitmapWords)}
+}
+
+func (c *bitmapContainer) add(x uint16) container {
+	w, bit := x/wordSize, uint64(1)<<(x%wordSize)
+	if c.words[w]&bit == 0 {
+		c.words[w] |= bit
+		c.card++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(x uint16) container {
+	w, bit := x/wordSize, uint64(1)<<(x%wordSize)
+	if c.words[w]&bit != 0 {
+		c.words[w] &^= bit
+		c.card--
+	}
+	if c.card <= arrayMaxSize {
+		return c.toArray()
+	}
+	return c
+}
+
+func (c *bitmapContainer) contains(x uint16) bool {
+	return c.words[x/wordSize]&(1<<(x%wordSize)) != 0
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.card
+}
+
+func (c *bitmapContainer) each(high uint32, fn func(val uint32) bool) bool {
+	for w, word := range c.words {
+		for word != 0 {
+			t := bits.TrailingZeros64(word)
+			if !fn(high | uint32(w*wordSize+t)) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) rank(x uint16) int {
+	n := 0
+	w := int(x / wordSize)
+	for _, word := range c.words[:w] {
+		n += bits.OnesCount64(word)
+	}
+	// The shift overflows to zero for the last bit of the word, so the mask
+	// has all bits set.
+	mask := uint64(1)<<(x%wordSize+1) - 1
+	return n + bits.OnesCount64(c.words[w]&mask)
+}
+
+func (c *bitmapContainer) selectAt(i int) uint16 {
+	for w, word := range c.words {
+		n := bits.OnesCount64(word)
+		if i < n {
+			for ; i > 0; i-- {
+				word &= word - 1
+			}
+			return uint16(w*wordSize + bits.TrailingZeros64(word))
+		}
+		i -= n
+	}
+	panic("set: select out of range")
+}
+
+func (c *bitmapContainer) numRuns() int {
+	n := 0
+	for w, word := range c.words {
+		// A run starts at every bit which is set while the previous bit is
+		// not set.
+		prev := word << 1
+		if w > 0 {
+			prev |= c.words[w-1] >> (wordSize - 1)
+		}
+		n += bits.OnesCount64(word &^ prev)
+	}
+	return n
+}
+
+func (c *bitmapContainer) toBitmap() *bitmapContainer {
+	return c
+}
+
+func (c *bitmapContainer) clone() container {
+	return &bitmapContainer{words: slices.Clone(c.words), card: c.card}
+}
+
+// toArray converts the container into an arrayContainer.
+func (c *bitmapContainer) toArray() *arrayContainer {
+	a := &arrayContainer{values: make([]uint16, 0, c.card)}
+	c.each(0, func(val uint32) bool {
+		a.values = append(a.values, uint16(val))
+		return true
+	})
+	return a
+}
+
+// normalize returns the smallest one of an arrayContainer and c. It returns nil
+// if c is empty.
+func (c *bitmapContainer) normalize() container {
+	switch {
+	case c.card == 0:
+		return nil
+	case c.card <= arrayMaxSize:
+		return c.toArray()
+	}
+	return c
+}
+
+// interval is a run of the consecutive values from start to last, inclusive.
+type interval struct {
+	start, last uint16
+}
+
+// runContainer stores the values as the sorted runs of the consecutive values.
+type runContainer struct {
+	runs []interval
+}
+
+// search returns the index of the first run which starts after x.
+func (c *runContainer) search(x uint16) int {
+	i, _ := slices.BinarySearchFunc(c.runs, x, func(r interval, x uint16) int {
+		if r.start <= x {
+			return -1
+		}
+		return 1
+	})
+	return i
+}
+
+func (c *runContainer) add(x uint16) container {
+	i := c.search(x)
+	prev := i - 1
+	if prev >= 0 && x <= c.runs[prev].last {
+		return c
+	}
+	mergePrev := prev >= 0 && c.runs[prev].last+1 == x
+	mergeNext := i < len(c.runs) && c.runs[i].start == x+1
+	switch {
+	case mergePrev && mergeNext:
+		c.runs[prev].last = c.runs[i].last
+		c.runs = slices.Delete(c.runs, i, i+1)
+	case mergePrev:
+		c.runs[prev].last = x
+	case mergeNext:
+		c.runs[i].start = x
+	default:
+		c.runs = slices.Insert(c.runs, i, interval{start: x, last: x})
+	}
+	return c
+}
+
+func (c *runContainer) remove(x uint16) container {
+	i := c.search(x) - 1
+	if i < 0 || x > c.runs[i].last {
+		return c
+	}
+	r := c.runs[i]
+	switch {
+	case r.start == r.last:
+		c.runs = slices.Delete(c.runs, i, i+1)
+	case x == r.start:
+		c.runs[i].start++
+	case x == r.last:
+		c.runs[i].last--
+	default:
+		c.runs[i].last = x - 1
+		c.runs = slices.Insert(c.runs, i+1, interval{start: x + 1, last: r.last})
+	}
+	return c
+}
+
+func (c *runContainer) contains(x uint16) bool {
+	i := c.search(x) - 1
+	return i >= 0 && x <= c.runs[i].last
+}
+
+func (c *runContainer) cardinality() int {
+	n := 0
+	for _, r := range c.runs {
+		n += int(r.last-r.start) + 1
+	}
+	return n
+}
+
+func (c *runContainer) each(high uint32, fn func(val uint32) bool) bool {
+	for _, r := range c.runs {
+		for x := uint32(r.start); x <= uint32(r.last); x++ {
+			if !fn(high | x) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *runContainer) rank(x uint16) int {
+	n := 0
+	for _, r := range c.runs {
+		if x < r.start {
+			break
+		}
+		if x <= r.last {
+			return n + int(x-r.start) + 1
+		}
+		n += int(r.last-r.start) + 1
+	}
+	return n
+}
+
+func (c *runContainer) selectAt(i int) uint16 {
+	for _, r := range c.runs {
+		n := int(r.last-r.start) + 1
+		if i < n {
+			return r.start + uint16(i)
+		}
+		i -= n
+	}
+	panic("set: select out of range")
+}
+
+func (c *runContainer) numRuns() int {
+	return len(c.runs)
+}
+
+func (c *runContainer) toBitmap() *bitmapContainer {
+	b := newBitmapContainer()
+	for _, r := range c.runs {
+		for x := uint32(r.start); x <= uint32(r.last); x++ {
+			b.words[x/wordSize] |= 1 << (x % wordSize)
+		}
+	}
+	b.card = c.cardinality()
+	return b
+}
+
+func (c *runContainer) clone() container {
+	return &runContainer{runs: slices.Clone(c.runs)}
+}
+
+// toArray converts c into an arrayContainer.
+func toArray(c container) *arrayContainer {
+	if a, ok := c.(*arrayContainer); ok {
+		return a
+	}
+	return c.toBitmap().toArray()
+}
+
+// toRun converts c into a runContainer.
+func toRun(c container) *runContainer {
+	if r, ok := c.(*runContainer); ok {
+		return r
+	}
+	r := &runContainer{runs: make([]interval, 0, c.numRuns())}
+	c.each(0, func(val uint32) bool {
+		x := uint16(val)
+		if n := len(r.runs); n > 0 && r.runs[n-1].last+1 == x {
+			r.runs[n-1].last = x
+		} else {
+			r.runs = append(r.runs, interval{start: x, last: x})
+		}
+		return true
+	})
+	return r
+}
+
+// optimize returns the container which takes the least space in the
+// serialized form among the array, the bitmap and the run containers.
+func optimize(c container) container {
+	card := c.cardinality()
+	switch {
+	case 2+4*c.numRuns() < min(2*card, bitmapSize):
+		return toRun(c)
+	case card <= arrayMaxSize:
+		return toArray(c)
+	}
+	return c.toBitmap()
+}
+
+// andContainers returns the intersection of a and b. It returns nil if the
+// intersection is empty.
+func andContainers(a, b container) container {
+	if _, ok := b.(*arrayContainer); ok {
+		a, b = b, a
+	}
+	if x, ok := a.(*arrayContainer); ok {
+		values := make([]uint16, 0, len(x.values))
+		for _, v := range x.values {
+			if b.contains(v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return &arrayContainer{values: values}
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+// orContainers returns the union of a and b.
+func orContainers(a, b container) container {
+	x, xok := a.(*arrayContainer)
+	y, yok := b.(*arrayContainer)
+	if xok && yok && len(x.values)+len(y.values) <= arrayMaxSize {
+		values := make([]uint16, 0, len(x.values)+len(y.values))
+		i, j := 0, 0
+		for i < len(x.values) && j < len(y.values) {
+			switch {
+			case x.values[i] < y.values[j]:
+				values = append(values, x.values[i])
+				i++
+			case x.values[i] > y.values[j]:
+				values = append(values, y.values[j])
+				j++
+			default:
+				values = append(values, x.values[i])
+				i++
+				j++
+			}
+		}
+		values = append(values, x.values[i:]...)
+		values = append(values, y.values[j:]...)
+		return &arrayContainer{values: values}
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+// andNotContainers returns the values of a which do not exist in b. It returns
+// nil if the result is empty.
+func andNotContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		values := make([]uint16, 0, len(x.values))
+		for _, v := range x.values {
+			if !b.contains(v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return &arrayContainer{values: values}
+	}
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// xorContainers returns the values which exist in only one of a and b. It
+// returns nil if the result is empty.
+func xorContainers(a, b container) container {
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// bitmapOp applies op on the words of a and b and returns the normalized
+// result.
+func bitmapOp(a, b container, op func(x, y uint64) uint64) container {
+	x, y := a.toBitmap(), b.toBitmap()
+	r := newBitmapContainer()
+	for i := range r.words {
+		r.words[i] = op(x.words[i], y.words[i])
+		r.card += bits.OnesCount64(r.words[i])
+	}
+	return r.normalize()
+}
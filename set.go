@@ -1,24 +1,77 @@
 package set
 
+import "iter"
+
 const (
 	// ThreadSafe is used in New() for creating ThreadSafeSet.
 	ThreadSafe = iota
 
 	// ThreadUnsafe is used in New() for creating ThreadUnsafeSet.
 	ThreadUnsafe
+
+	// Ordered is used in New() for creating OrderedSet which sorts its values
+	// with Compare.
+	Ordered
+
+	// InsertionOrdered is used in New() for creating InsertionOrderedSet which
+	// remembers the insertion order of its values.
+	InsertionOrdered
+
+	// Sharded is used in New() for creating ShardedSet which splits its values
+	// into the independently locked shards.
+	Sharded
+
+	// CopyOnWrite is used in New() for creating CopyOnWriteSet whose reads do
+	// not take any lock.
+	CopyOnWrite
 )
 
-// Set is set interface.
-type Set interface {
+// Membership is the adding and membership half of the Set interface. It is
+// also implemented by the probabilistic sets such as BloomFilter which cannot
+// list their values.
+type Membership interface {
 	Add(val interface{})
 	Append(val ...interface{})
-	Remove(val interface{})
 	Contains(val interface{}) bool
+}
+
+// Set is set interface. The binary operations such as Union, Intersection or
+// Equal accept any Set implementation, so thread-safe and thread-unsafe sets can
+// be mixed. The sets returned from the binary operations have the same kind as
+// the receiver set.
+//
+// Each and All walk the set without copying it. The thread-safe sets hold their
+// read lock during the iteration, so the set must not be modified from the
+// callback or from the loop body. The thread-unsafe sets follow the rules of
+// the Go maps: removing the values is safe, but the added values may or may not
+// be visited.
+//
+// Filter, Map, Reduce, Partition, AnyMatch, AllMatch and CountIf walk the set in
+// the same way with Each, so the same rules apply to the given functions. The
+// sets returned from them have the same kind as the receiver set.
+//
+// TryPop, Peek, Each, All and the functional methods were added after the
+// first release, so the implementations outside of this package must implement
+// them. See the breaking changes in the README.
+type Set interface {
+	Membership
+	Remove(val interface{})
 	Size() uint
 	Pop() interface{}
+	TryPop() (interface{}, bool)
+	Peek() interface{}
 	Clear()
 	Empty() bool
 	Slice() []interface{}
+	Each(fn func(val interface{}) bool)
+	All() iter.Seq[interface{}]
+	Filter(pred func(val interface{}) bool) Set
+	Map(fn func(val interface{}) interface{}) Set
+	Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{}
+	Partition(pred func(val interface{}) bool) (Set, Set)
+	AnyMatch(pred func(val interface{}) bool) bool
+	AllMatch(pred func(val interface{}) bool) bool
+	CountIf(pred func(val interface{}) bool) uint
 	Union(set Set) Set
 	Intersection(set Set) Set
 	Difference(set Set) Set
@@ -40,6 +93,10 @@ var setVal = struct{}{}
 //
 //	safeSet := New(set.ThreadSafe)	// Creates a thread-safe set.
 //	unsafeSet := New(set.ThreadUnsafe)	// Creates a thread-unsafe set.
+//	orderedSet := New(set.Ordered)	// Creates a sorted set.
+//	insertionOrderedSet := New(set.InsertionOrdered)	// Creates an insertion-ordered set.
+//	shardedSet := New(set.Sharded)	// Creates a thread-safe set with striped locks.
+//	cowSet := New(set.CopyOnWrite)	// Creates a thread-safe set with lock-free reads.
 func New(t setType) Set {
 	var set Set
 	switch t {
@@ -47,6 +104,24 @@ func New(t setType) Set {
 		set = newThreadSafeSet()
 	case ThreadUnsafe:
 		set = newThreadUnsafeSet()
+	case Ordered:
+		set = NewOrdered(nil)
+	case InsertionOrdered:
+		set = NewInsertionOrdered()
+	case Sharded:
+		set = NewSharded(defaultShards())
+	case CopyOnWrite:
+		set = NewCopyOnWrite()
 	}
 	return set
 }
+
+// NewOf creates a set regarding setType like New and adds the given values into
+// it. The Go syntax of the sets, which is printed by %#v, uses NewOf.
+//
+//	s := set.NewOf(set.ThreadUnsafe, 1, 2, 3)
+func NewOf(t setType, values ...interface{}) Set {
+	set := New(t)
+	set.Append(values...)
+	return set
+}
@@ -0,0 +1,91 @@
+package set
+
+import (
+	"testing"
+)
+
+// foreignSet is a Set implementation which is neither *ThreadSafeSet nor
+// *ThreadUnsafeSet. It is used for testing the operations between different
+// Set implementations.
+type foreignSet struct {
+	Set
+}
+
+func TestSet_MixedOperations(t *testing.T) {
+	testCases := []struct {
+		name    string
+		set1    Set
+		set2    Set
+		expSafe bool
+	}{
+		{
+			name:    "Thread-safe and thread-unsafe",
+			set1:    New(ThreadSafe),
+			set2:    New(ThreadUnsafe),
+			expSafe: true,
+		},
+		{
+			name: "Thread-unsafe and thread-safe",
+			set1: New(ThreadUnsafe),
+			set2: New(ThreadSafe),
+		},
+		{
+			name:    "Thread-safe and foreign",
+			set1:    New(ThreadSafe),
+			set2:    foreignSet{New(ThreadUnsafe)},
+			expSafe: true,
+		},
+		{
+			name: "Thread-unsafe and foreign",
+			set1: New(ThreadUnsafe),
+			set2: foreignSet{New(ThreadSafe)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.set1.Append(1, 2, 3)
+			tc.set2.Append(2, 3, 4)
+
+			checkSet(t, "union", tc.set1.Union(tc.set2), tc.expSafe, 1, 2, 3, 4)
+			checkSet(t, "intersection", tc.set1.Intersection(tc.set2), tc.expSafe, 2, 3)
+			checkSet(t, "difference", tc.set1.Difference(tc.set2), tc.expSafe, 1)
+			checkSet(t, "symmetric difference", tc.set1.SymmetricDifference(tc.set2), tc.expSafe, 1, 4)
+			if tc.set1.IsSubset(tc.set2) {
+				t.Errorf("expected not subset")
+			}
+			if tc.set1.IsSuperset(tc.set2) {
+				t.Errorf("expected not superset")
+			}
+			if tc.set1.IsDisjoint(tc.set2) {
+				t.Errorf("expected not disjoint")
+			}
+			if tc.set1.Equal(tc.set2) {
+				t.Errorf("expected not equal")
+			}
+
+			tc.set2.Remove(4)
+			tc.set2.Add(1)
+			if !tc.set1.IsSubset(tc.set2) || !tc.set1.IsSuperset(tc.set2) || !tc.set1.Equal(tc.set2) {
+				t.Errorf("expected equal sets")
+			}
+		})
+	}
+}
+
+// checkSet checks whether s contains exactly the expected values and whether s
+// has the expected thread-safety.
+func checkSet(t *testing.T, name string, s Set, expSafe bool, exp ...interface{}) {
+	t.Helper()
+	if _, ok := s.(*ThreadSafeSet); ok != expSafe {
+		t.Errorf("%s: expected thread-safe %v, actual %T", name, expSafe, s)
+	}
+	if s.Size() != uint(len(exp)) {
+		t.Errorf("%s: expected size %v, actual size %v", name, len(exp), s.Size())
+	}
+	for _, val := range exp {
+		if !s.Contains(val) {
+			t.Errorf("%s: expected %v, but not exists", name, val)
+		}
+	}
+}
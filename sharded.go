@@ -0,0 +1,325 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ShardedSet is a thread-safe set type which splits its values into the
+// independently locked shards by their hashes. The writers of the different
+// shards do not block each other, so it scales better than ThreadSafeSet under
+// the concurrent writes.
+//
+// Add, Remove, Contains and Pop lock a single shard. Size sums the counters of
+// the shards without locking them, so it is cheap, but it may not include the
+// concurrent writes which are still in progress. The methods which read the
+// whole set, such as Slice, Each and the functional methods, hold the read locks
+// of all shards, so they see a consistent snapshot of the set. Clear holds the
+// write locks of all shards. The binary operations read the given set first and
+// then the receiver set, so each set is read consistently but not at the same
+// moment, like ThreadSafeSet with the other Set implementations.
+type ShardedSet struct {
+	shards []shard
+	seed   maphash.Seed
+}
+
+// shard is a part of ShardedSet with its own lock and counter. It is padded to
+// a cache line, so the writers of the neighbour shards do not slow down each
+// other.
+type shard struct {
+	set  map[interface{}]struct{}
+	rw   sync.RWMutex
+	size atomic.Int64
+	_    [64]byte
+}
+
+// NewSharded creates a new *ShardedSet with n shards. n is rounded up to a
+// power of 2. New(Sharded) creates a set with 4 shards per CPU.
+//
+//	s := set.NewSharded(64)
+func NewSharded(n uint) *ShardedSet {
+	n = 1 << bits.Len(max(n, 1)-1)
+	s := &ShardedSet{shards: make([]shard, n), seed: maphash.MakeSeed()}
+	for i := range s.shards {
+		s.shards[i].set = make(map[interface{}]struct{})
+	}
+	return s
+}
+
+// defaultShards returns the number of the shards for New(Sharded).
+func defaultShards() uint {
+	return uint(4 * runtime.GOMAXPROCS(0))
+}
+
+// shardOf returns the shard of val.
+func (s *ShardedSet) shardOf(val interface{}) *shard {
+	h := maphash.Comparable(s.seed, val)
+	return &s.shards[h&uint64(len(s.shards)-1)]
+}
+
+// empty returns a new empty set with the same number of shards.
+func (s *ShardedSet) empty() *ShardedSet {
+	return NewSharded(uint(len(s.shards)))
+}
+
+// rLockAll acquires the read locks of all shards in order and returns the
+// function which releases them.
+func (s *ShardedSet) rLockAll() func() {
+	for i := range s.shards {
+		s.shards[i].rw.RLock()
+	}
+	return func() {
+		for i := len(s.shards) - 1; i >= 0; i-- {
+			s.shards[i].rw.RUnlock()
+		}
+	}
+}
+
+// Shards returns the number of the shards.
+func (s *ShardedSet) Shards() uint {
+	return uint(len(s.shards))
+}
+
+// Add adds a new value to set.
+func (s *ShardedSet) Add(val interface{}) {
+	sh := s.shardOf(val)
+	sh.rw.Lock()
+	defer sh.rw.Unlock()
+	if _, ok := sh.set[val]; !ok {
+		sh.set[val] = setVal
+		sh.size.Add(1)
+	}
+}
+
+// Append adds multiple values into set. The values are added one by one, so
+// the other goroutines may observe a part of them.
+func (s *ShardedSet) Append(values ...interface{}) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value.
+func (s *ShardedSet) Remove(val interface{}) {
+	sh := s.shardOf(val)
+	sh.rw.Lock()
+	defer sh.rw.Unlock()
+	if _, ok := sh.set[val]; ok {
+		delete(sh.set, val)
+		sh.size.Add(-1)
+	}
+}
+
+// Contains checks the value whether exists in the set.
+func (s *ShardedSet) Contains(val interface{}) bool {
+	sh := s.shardOf(val)
+	sh.rw.RLock()
+	defer sh.rw.RUnlock()
+	_, ok := sh.set[val]
+	return ok
+}
+
+// Size returns the length of the set by summing the counters of the shards.
+// It does not lock the shards.
+func (s *ShardedSet) Size() uint {
+	var n int64
+	for i := range s.shards {
+		n += s.shards[i].size.Load()
+	}
+	return uint(max(n, 0))
+}
+
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns nil. Use TryPop for distinguishing a nil value
+// from the empty set.
+func (s *ShardedSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns nil and false. The shards are locked one by
+// one, so it may return false while the other goroutines add values.
+func (s *ShardedSet) TryPop() (interface{}, bool) {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		if sh.size.Load() == 0 {
+			continue
+		}
+		sh.rw.Lock()
+		for val := range sh.set {
+			delete(sh.set, val)
+			sh.size.Add(-1)
+			sh.rw.Unlock()
+			return val, true
+		}
+		sh.rw.Unlock()
+	}
+	return nil, false
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns nil.
+func (s *ShardedSet) Peek() interface{} {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.rw.RLock()
+		for val := range sh.set {
+			sh.rw.RUnlock()
+			return val
+		}
+		sh.rw.RUnlock()
+	}
+	return nil
+}
+
+// Clear removes everything from the set. It holds the write locks of all
+// shards, so no goroutine can observe a partially cleared set.
+func (s *ShardedSet) Clear() {
+	for i := range s.shards {
+		s.shards[i].rw.Lock()
+	}
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		sh := &s.shards[i]
+		sh.set = make(map[interface{}]struct{})
+		sh.size.Store(0)
+		sh.rw.Unlock()
+	}
+}
+
+// Empty checks whether the set is empty.
+func (s *ShardedSet) Empty() bool {
+	return s.Size() == 0
+}
+
+// Slice returns the values of the set as a slice. The values are read under
+// the read locks of all shards, so the slice is a snapshot of the set.
+func (s *ShardedSet) Slice() []interface{} {
+	unlock := s.rLockAll()
+	defer unlock()
+	values := make([]interface{}, 0, s.Size())
+	for i := range s.shards {
+		for val := range s.shards[i].set {
+			values = append(values, val)
+		}
+	}
+	return values
+}
+
+// Each calls fn for every value in the set until fn returns false. The read
+// locks of all shards are held during the iteration, so the set must not be
+// modified from fn.
+func (s *ShardedSet) Each(fn func(val interface{}) bool) {
+	unlock := s.rLockAll()
+	defer unlock()
+	for i := range s.shards {
+		for val := range s.shards[i].set {
+			if !fn(val) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the values of the set. It follows the same rules
+// with Each, so the set must not be modified from the loop body.
+func (s *ShardedSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred. pred
+// is called under the read locks, so it must not modify the set.
+func (s *ShardedSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, s.empty(), pred)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. fn is called under the read locks, so it must not modify the set.
+func (s *ShardedSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, s.empty(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. The values are visited in any order. fn is
+// called under the read locks, so it must not modify the set.
+func (s *ShardedSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the values
+// which satisfy pred and the second one contains the rest. pred is called under
+// the read locks, so it must not modify the set.
+func (s *ShardedSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, s.empty(), s.empty(), pred)
+}
+
+// AnyMatch returns true if at least one value satisfies pred. pred is called
+// under the read locks, so it must not modify the set.
+func (s *ShardedSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values satisfy pred. It returns true for an
+// empty set. pred is called under the read locks, so it must not modify the
+// set.
+func (s *ShardedSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of the values which satisfy pred. pred is called
+// under the read locks, so it must not modify the set.
+func (s *ShardedSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new *ShardedSet that contains all items from the receiver
+// set and all items from the given set. The given set can be any Set
+// implementation.
+func (s *ShardedSet) Union(set Set) Set {
+	return union(s.empty(), s, set)
+}
+
+// Intersection takes the common values from both sets and returns a new
+// *ShardedSet that stores the common ones. The given set can be any Set
+// implementation.
+func (s *ShardedSet) Intersection(set Set) Set {
+	return intersection(s.empty(), s, set)
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new *ShardedSet. The given set can be any Set implementation.
+func (s *ShardedSet) Difference(set Set) Set {
+	return difference(s.empty(), s, set)
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false.
+func (s *ShardedSet) IsSubset(set Set) bool {
+	return isSubset(s, set)
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false.
+func (s *ShardedSet) IsSuperset(set Set) bool {
+	return isSubset(set, s)
+}
+
+// IsDisjoint returns true if none of the items are present in the sets.
+func (s *ShardedSet) IsDisjoint(set Set) bool {
+	return isDisjoint(s, set)
+}
+
+// Equal checks whether both sets contain exactly the same values.
+func (s *ShardedSet) Equal(set Set) bool {
+	return equal(s, set)
+}
+
+// SymmetricDifference returns a new *ShardedSet that contains the items which
+// only exist in one of the sets.
+func (s *ShardedSet) SymmetricDifference(set Set) Set {
+	return symmetricDifference(s.empty(), s, set)
+}
@@ -0,0 +1,163 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShardedSet_New(t *testing.T) {
+	testCases := []struct {
+		name      string
+		n         uint
+		expShards uint
+	}{
+		{name: "Zero shards", n: 0, expShards: 1},
+		{name: "Power of 2", n: 16, expShards: 16},
+		{name: "Rounded up", n: 20, expShards: 32},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if s := NewSharded(tc.n); s.Shards() != tc.expShards {
+				t.Errorf("expected %v shards, actual %v", tc.expShards, s.Shards())
+			}
+		})
+	}
+
+	s, ok := New(Sharded).(*ShardedSet)
+	if !ok {
+		t.Fatalf("New(Sharded) did not return *ShardedSet")
+	}
+	if s.Shards() < defaultShards() {
+		t.Errorf("expected at least %v shards, actual %v", defaultShards(), s.Shards())
+	}
+}
+
+func TestShardedSet_Basic(t *testing.T) {
+	s := NewSharded(8)
+	if !s.Empty() || s.Peek() != nil {
+		t.Errorf("new set is not empty")
+	}
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+	s.Append(0, 1, "str", nil)
+	if s.Size() != 102 {
+		t.Errorf("expected size 102, actual %v", s.Size())
+	}
+	if !s.Contains(nil) || !s.Contains("str") || s.Contains(100) {
+		t.Errorf("unexpected Contains result")
+	}
+
+	s.Remove("str")
+	s.Remove("missing")
+	s.Remove(nil)
+	values := s.Slice()
+	if len(values) != 100 {
+		t.Fatalf("expected 100 values, actual %v", len(values))
+	}
+	ints := make([]int, 0, len(values))
+	for _, val := range values {
+		ints = append(ints, val.(int))
+	}
+	slices.Sort(ints)
+	for i, val := range ints {
+		if val != i {
+			t.Fatalf("expected %v, actual %v", i, val)
+		}
+	}
+
+	if s.Peek() == nil {
+		t.Errorf("Peek returned nil for a non-empty set")
+	}
+	for i := 0; i < 100; i++ {
+		if _, ok := s.TryPop(); !ok {
+			t.Fatalf("TryPop failed at %v", i)
+		}
+	}
+	if val, ok := s.TryPop(); ok || s.Pop() != nil {
+		t.Errorf("expected empty set, popped %v", val)
+	}
+
+	s.Append(1, 2, 3)
+	s.Clear()
+	if !s.Empty() || s.Contains(1) {
+		t.Errorf("set is not empty after Clear")
+	}
+}
+
+func TestShardedSet_Functional(t *testing.T) {
+	s := NewSharded(4)
+	s.Append(1, 2, 3, 4, 5, 6)
+
+	evens := s.Filter(isEven)
+	if _, ok := evens.(*ShardedSet); !ok {
+		t.Errorf("Filter returned %T", evens)
+	}
+	checkSameSet(t, "Filter", evens, NewOf(ThreadUnsafe, 2, 4, 6))
+	checkSameSet(t, "Map", s.Map(func(val interface{}) interface{} { return val.(int) % 3 }),
+		NewOf(ThreadUnsafe, 0, 1, 2))
+	in, out := s.Partition(isEven)
+	checkSameSet(t, "Partition in", in, NewOf(ThreadUnsafe, 2, 4, 6))
+	checkSameSet(t, "Partition out", out, NewOf(ThreadUnsafe, 1, 3, 5))
+
+	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+	if sum != 21 {
+		t.Errorf("expected sum 21, actual %v", sum)
+	}
+	if !s.AnyMatch(isEven) || s.AllMatch(isEven) || s.CountIf(isEven) != 3 {
+		t.Errorf("unexpected match results")
+	}
+
+	var visited []int
+	for val := range s.All() {
+		visited = append(visited, val.(int))
+		if len(visited) == 2 {
+			break
+		}
+	}
+	if len(visited) != 2 {
+		t.Errorf("expected 2 visited values, actual %v", visited)
+	}
+}
+
+func TestShardedSet_Operations(t *testing.T) {
+	others := []struct {
+		name string
+		set  Set
+	}{
+		{name: "Sharded", set: NewSharded(2)},
+		{name: "ThreadSafe", set: New(ThreadSafe)},
+		{name: "ThreadUnsafe", set: New(ThreadUnsafe)},
+		{name: "Foreign", set: foreignSet{New(ThreadUnsafe)}},
+	}
+
+	for _, o := range others {
+		t.Run(o.name, func(t *testing.T) {
+			s := NewSharded(8)
+			s.Append(1, 2, 3)
+			o.set.Append(2, 3, 4)
+
+			union := s.Union(o.set)
+			if _, ok := union.(*ShardedSet); !ok {
+				t.Errorf("Union returned %T", union)
+			}
+			checkSameSet(t, "Union", union, NewOf(ThreadUnsafe, 1, 2, 3, 4))
+			checkSameSet(t, "Intersection", s.Intersection(o.set), NewOf(ThreadUnsafe, 2, 3))
+			checkSameSet(t, "Difference", s.Difference(o.set), NewOf(ThreadUnsafe, 1))
+			checkSameSet(t, "SymmetricDifference", s.SymmetricDifference(o.set), NewOf(ThreadUnsafe, 1, 4))
+			if s.IsSubset(o.set) || s.IsSuperset(o.set) || s.IsDisjoint(o.set) || s.Equal(o.set) {
+				t.Errorf("unexpected comparison result")
+			}
+
+			o.set.Remove(4)
+			o.set.Add(1)
+			if !s.IsSubset(o.set) || !s.IsSuperset(o.set) || !s.Equal(o.set) || !o.set.Equal(s) {
+				t.Errorf("expected equal sets")
+			}
+			if len(s.Slice()) != 3 {
+				t.Errorf("unexpected Slice %v", s.Slice())
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package set
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SQLFormat is the text format of a set in a database column.
+type SQLFormat uint8
+
+const (
+	// PostgresArray is the array literal of PostgreSQL such as {a,b,"c d"}.
+	PostgresArray SQLFormat = iota
+	// JSONArray is the JSON array text such as ["a","b","c d"].
+	JSONArray
+)
+
+// SQLSet wraps a Set for storing it in a database column. It implements
+// driver.Valuer and sql.Scanner, so it can be used as a query argument and as a
+// Scan destination.
+//
+// Value writes the values in Format, sorted with Compare so the same set is
+// always stored with the same text. Scan detects the format from the text, so
+// both formats can be read. The values read from a PostgreSQL array are
+// strings, and the integers read from a JSON array are int.
+//
+//	_, err := db.Exec("UPDATE posts SET tags = $1", set.NewSQLSet(tags, set.PostgresArray))
+//	var dst set.SQLSet
+//	err = db.QueryRow("SELECT tags FROM posts").Scan(&dst)
+type SQLSet struct {
+	Set    Set
+	Format SQLFormat
+	// Valid is false if the column is NULL.
+	Valid bool
+}
+
+// NewSQLSet creates a new SQLSet which stores s in the given format. The
+// SQLSet is NULL if s is nil.
+func NewSQLSet(s Set, format SQLFormat) SQLSet {
+	return SQLSet{Set: s, Format: format, Valid: s != nil}
+}
+
+// Value returns the text of the set in Format, or nil for NULL. It returns an
+// error which wraps ErrUnsupportedType if a value cannot be written in Format.
+func (s SQLSet) Value() (driver.Value, error) {
+	if !s.Valid || s.Set == nil {
+		return nil, nil
+	}
+	switch s.Format {
+	case PostgresArray:
+		return formatPostgresArray(s.Set.Slice())
+	case JSONArray:
+		data, err := encodeJSON(s.Set.Slice(), jsonConfig{sorted: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+		}
+		return string(data), nil
+	}
+	return nil, fmt.Errorf("set: unknown SQL format %d", s.Format)
+}
+
+// Scan replaces the values of Set with the values in src, which must be a
+// PostgreSQL array or a JSON array as string or []byte. A new ThreadUnsafe set
+// is created if Set is nil. For NULL, Set is cleared and Valid is set to false.
+// Format is set to the format of the text. It returns an error which wraps
+// ErrInvalidFormat if the text is not valid, and the SQLSet is not changed in
+// that case.
+func (s *SQLSet) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		s.Valid = false
+		if s.Set != nil {
+			s.Set.Clear()
+		}
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into SQLSet", ErrUnsupportedType, src)
+	}
+
+	var values []interface{}
+	var format SQLFormat
+	var err error
+	switch trimmed := strings.TrimSpace(text); {
+	case strings.HasPrefix(trimmed, "{"):
+		format = PostgresArray
+		values, err = parsePostgresArray(trimmed)
+	case strings.HasPrefix(trimmed, "["):
+		format = JSONArray
+		values, err = decodeJSON([]byte(trimmed), jsonConfig{numbers: JSONInt})
+	default:
+		err = fmt.Errorf("%w: %q is not an array", ErrInvalidFormat, text)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.Set == nil {
+		s.Set = New(ThreadUnsafe)
+	}
+	replaceValues(s.Set, values)
+	s.Format = format
+	s.Valid = true
+	return nil
+}
+
+// formatPostgresArray returns the PostgreSQL array literal of values. The
+// strings, bools, numbers and nil are supported.
+func formatPostgresArray(values []interface{}) (string, error) {
+	slices.SortFunc(values, Compare)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, val := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if val == nil {
+			b.WriteString("NULL")
+			continue
+		}
+		v := reflect.ValueOf(val)
+		switch v.Kind() {
+		case reflect.String:
+			writePostgresElement(&b, v.String())
+		case reflect.Bool:
+			b.WriteString(strconv.FormatBool(v.Bool()))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			b.WriteString(strconv.FormatInt(v.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			b.WriteString(strconv.FormatUint(v.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			switch {
+			case math.IsInf(f, 1):
+				b.WriteString("Infinity")
+			case math.IsInf(f, -1):
+				b.WriteString("-Infinity")
+			default:
+				b.WriteString(strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+			}
+		default:
+			return "", fmt.Errorf("%w: %T in PostgreSQL array", ErrUnsupportedType, val)
+		}
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// writePostgresElement writes str as an element of an array literal. The
+// element is quoted if it is empty, it is NULL, or it contains a special
+// character or a space.
+func writePostgresElement(b *strings.Builder, str string) {
+	if str != "" && !strings.EqualFold(str, "NULL") && !strings.ContainsAny(str, "{}\",\\ \t\n\r\v\f") {
+		b.WriteString(str)
+		return
+	}
+	b.WriteByte('"')
+	for i := 0; i < len(str); i++ {
+		if str[i] == '"' || str[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(str[i])
+	}
+	b.WriteByte('"')
+}
+
+// parsePostgresArray parses a one-dimensional PostgreSQL array literal. The
+// elements are returned as strings, and the unquoted NULL elements as nil.
+func parsePostgresArray(text string) ([]interface{}, error) {
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, fmt.Errorf("%w: %q is not a PostgreSQL array", ErrInvalidFormat, text)
+	}
+	p := postgresParser{text: text[1 : len(text)-1]}
+	values := []interface{}{}
+	if p.skipSpaces(); p.done() {
+		return values, nil
+	}
+	for {
+		val, err := p.element()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, val)
+		if p.skipSpaces(); p.done() {
+			return values, nil
+		}
+		if p.text[p.i] != ',' {
+			return nil, fmt.Errorf("%w: unexpected %q in PostgreSQL array", ErrInvalidFormat, p.text[p.i])
+		}
+		p.i++
+		p.skipSpaces()
+	}
+}
+
+// postgresParser reads the elements of an array literal without its braces.
+type postgresParser struct {
+	text string
+	i    int
+}
+
+// done returns true if all text is read.
+func (p *postgresParser) done() bool {
+	return p.i == len(p.text)
+}
+
+// skipSpaces skips the spaces between the elements.
+func (p *postgresParser) skipSpaces() {
+	for !p.done() && isPostgresSpace(p.text[p.i]) {
+		p.i++
+	}
+}
+
+// element reads a quoted or an unquoted element. The backslash escapes the
+// next character in both of them.
+func (p *postgresParser) element() (interface{}, error) {
+	var b strings.Builder
+	if !p.done() && p.text[p.i] == '"' {
+		for p.i++; !p.done(); p.i++ {
+			switch c := p.text[p.i]; c {
+			case '"':
+				p.i++
+				return b.String(), nil
+			case '\\':
+				if p.i++; p.done() {
+					return nil, fmt.Errorf("%w: unterminated PostgreSQL array element", ErrInvalidFormat)
+				}
+				b.WriteByte(p.text[p.i])
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return nil, fmt.Errorf("%w: unterminated PostgreSQL array element", ErrInvalidFormat)
+	}
+
+	// The trailing spaces of an unquoted element are ignored unless they are
+	// escaped, so end is the length of the element without them.
+	end, escaped := 0, false
+	for ; !p.done() && p.text[p.i] != ','; p.i++ {
+		switch c := p.text[p.i]; c {
+		case '{', '}', '"':
+			return nil, fmt.Errorf("%w: unexpected %q in PostgreSQL array", ErrInvalidFormat, c)
+		case '\\':
+			if p.i++; p.done() {
+				return nil, fmt.Errorf("%w: unterminated PostgreSQL array element", ErrInvalidFormat)
+			}
+			b.WriteByte(p.text[p.i])
+			end, escaped = b.Len(), true
+		default:
+			b.WriteByte(c)
+			if !isPostgresSpace(c) {
+				end = b.Len()
+			}
+		}
+	}
+	str := b.String()[:end]
+	if str == "" {
+		return nil, fmt.Errorf("%w: empty PostgreSQL array element", ErrInvalidFormat)
+	}
+	if !escaped && strings.EqualFold(str, "NULL") {
+		return nil, nil
+	}
+	return str, nil
+}
+
+// isPostgresSpace returns true for the spaces which are ignored around the
+// elements of an array literal.
+func isPostgresSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package set
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a database driver which stores a single column. The strings
+// are stored as []byte like the real drivers.
+type fakeDriver struct {
+	mu    sync.Mutex
+	value driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) Driver() driver.Driver {
+	return d
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, insert: query == "INSERT"}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	d      *fakeDriver
+	insert bool
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	if s.insert {
+		return 1
+	}
+	return 0
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.value = args[0]
+	if str, ok := args[0].(string); ok {
+		s.d.value = []byte(str)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{value: s.d.value}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"values"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestSQLSet_Database(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		format   SQLFormat
+		expText  string
+		expSet   []interface{}
+		expValid bool
+	}{
+		{
+			name:     "Postgres array",
+			values:   []interface{}{"b", "a", "c d", `q"uote`, `back\slash`, "", "NULL", nil, 2, true},
+			format:   PostgresArray,
+			expText:  `{NULL,true,2,"","NULL",a,b,"back\\slash","c d","q\"uote"}`,
+			expSet:   []interface{}{"b", "a", "c d", `q"uote`, `back\slash`, "", "NULL", nil, "2", "true"},
+			expValid: true,
+		},
+		{
+			name:     "JSON array",
+			values:   []interface{}{"b", "a", "c d", nil, 2, 1.5, true},
+			format:   JSONArray,
+			expText:  `[null,true,1.5,2,"a","b","c d"]`,
+			expSet:   []interface{}{"b", "a", "c d", nil, 2, 1.5, true},
+			expValid: true,
+		},
+		{
+			name:     "Empty Postgres array",
+			format:   PostgresArray,
+			expText:  `{}`,
+			expSet:   []interface{}{},
+			expValid: true,
+		},
+		{
+			name:   "NULL",
+			format: JSONArray,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &fakeDriver{}
+			db := sql.OpenDB(d)
+			defer db.Close()
+
+			var s Set
+			if tc.values != nil || tc.expValid {
+				s = New(ThreadSafe)
+				s.Append(tc.values...)
+			}
+			if _, err := db.Exec("INSERT", NewSQLSet(s, tc.format)); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tc.expValid && string(d.value.([]byte)) != tc.expText {
+				t.Errorf("expected %s, actual %s", tc.expText, d.value)
+			}
+			if !tc.expValid && d.value != nil {
+				t.Errorf("expected NULL, actual %v", d.value)
+			}
+
+			dst := SQLSet{Set: New(ThreadUnsafe)}
+			dst.Set.Add("old")
+			if err := db.QueryRow("SELECT").Scan(&dst); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if dst.Valid != tc.expValid || (tc.expValid && dst.Format != tc.format) {
+				t.Errorf("expected valid %v and format %v, actual %v and %v", tc.expValid, tc.format, dst.Valid, dst.Format)
+			}
+			checkSameSet(t, tc.name, dst.Set, NewOf(ThreadUnsafe, tc.expSet...))
+		})
+	}
+}
+
+func TestSQLSet_ScanPostgresArray(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		exp  []interface{}
+	}{
+		{name: "Spaces", text: ` { a , b c ,  "d " } `, exp: []interface{}{"a", "b c", "d "}},
+		{name: "Escapes", text: `{a\,b,"c\"d",e\ ,\NULL}`, exp: []interface{}{"a,b", `c"d`, "e ", "NULL"}},
+		{name: "NULL", text: `{null,NULL,"null"}`, exp: []interface{}{nil, "null"}},
+		{name: "Unicode", text: `{ğ,"ş ç"}`, exp: []interface{}{"ğ", "ş ç"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dst SQLSet
+			if err := dst.Scan(tc.text); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if _, ok := dst.Set.(*ThreadUnsafeSet); !ok || !dst.Valid {
+				t.Errorf("expected valid *ThreadUnsafeSet, actual %T", dst.Set)
+			}
+			checkSameSet(t, tc.name, dst.Set, NewOf(ThreadUnsafe, tc.exp...))
+		})
+	}
+}
+
+func TestSQLSet_ScanInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		src    interface{}
+		expErr error
+	}{
+		{name: "Not array", src: "abc", expErr: ErrInvalidFormat},
+		{name: "Unterminated array", src: "{a,b", expErr: ErrInvalidFormat},
+		{name: "Unterminated quote", src: `{"a}`, expErr: ErrInvalidFormat},
+		{name: "Empty element", src: "{a,,b}", expErr: ErrInvalidFormat},
+		{name: "Trailing comma", src: "{a,}", expErr: ErrInvalidFormat},
+		{name: "Nested array", src: "{{a},{b}}", expErr: ErrInvalidFormat},
+		{name: "Text after quote", src: `{"a"b}`, expErr: ErrInvalidFormat},
+		{name: "Nested JSON array", src: `[["a"]]`, expErr: ErrInvalidFormat},
+		{name: "Unsupported source", src: 12, expErr: ErrUnsupportedType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := NewSQLSet(New(ThreadUnsafe), PostgresArray)
+			dst.Set.Add("old")
+			if err := dst.Scan(tc.src); !errors.Is(err, tc.expErr) {
+				t.Errorf("expected %v, actual %v", tc.expErr, err)
+			}
+			if !reflect.DeepEqual(dst.Set.Slice(), []interface{}{"old"}) {
+				t.Errorf("expected unchanged set, actual %v", dst.Set.Slice())
+			}
+		})
+	}
+}
+
+func TestSQLSet_ValueUnsupported(t *testing.T) {
+	for _, format := range []SQLFormat{PostgresArray, JSONArray} {
+		s := New(ThreadUnsafe)
+		s.Add(complex(1, 2))
+		if _, err := NewSQLSet(s, format).Value(); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("expected ErrUnsupportedType for format %v, actual %v", format, err)
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package set
 
-import "sync"
+import (
+	"iter"
+	"sync"
+	"unsafe"
+)
 
 // ThreadSafeSet is a set type which provides the thread-safety.
 type ThreadSafeSet struct {
-	set map[interface{}]struct{}
-	rw  sync.RWMutex
+	set      map[interface{}]struct{}
+	rw       sync.RWMutex
+	watchers []*watcher
+	seq      uint64
 }
 
 // newThreadSafeSet creates a new *ThreadSafeSet.
@@ -16,20 +22,23 @@ func newThreadSafeSet() *ThreadSafeSet {
 // Add adds a new values to set.
 func (s *ThreadSafeSet) Add(val interface{}) {
 	s.rw.Lock()
-	defer s.rw.Unlock()
+	defer s.unlock()
 	s.add(val)
 }
 
 // add is the implementation of the Add method which is not thread-safety.
 // It is called by other methods for avoiding deadlock.
 func (s *ThreadSafeSet) add(val interface{}) {
+	if s.watchers != nil && !s.contains(val) {
+		s.emit(ChangeAdded, val)
+	}
 	s.set[val] = setVal
 }
 
 // Append adds multiple values into set.
 func (s *ThreadSafeSet) Append(values ...interface{}) {
 	s.rw.Lock()
-	defer s.rw.Unlock()
+	defer s.unlock()
 	for _, val := range values {
 		s.add(val)
 	}
@@ -38,7 +47,16 @@ func (s *ThreadSafeSet) Append(values ...interface{}) {
 // Remove deletes the given value.
 func (s *ThreadSafeSet) Remove(val interface{}) {
 	s.rw.Lock()
-	defer s.rw.Unlock()
+	defer s.unlock()
+	s.remove(val)
+}
+
+// remove is the implementation of the Remove method which is not
+// thread-safety. It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeSet) remove(val interface{}) {
+	if s.watchers != nil && s.contains(val) {
+		s.emit(ChangeRemoved, val)
+	}
 	delete(s.set, val)
 }
 
@@ -69,9 +87,50 @@ func (s *ThreadSafeSet) size() uint {
 	return uint(len(s.set))
 }
 
-// Pop returns a random value from the set. If there is no element in set, it
-// returns nil. It does not remove any elements from the set.
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns nil. Use TryPop for distinguishing a nil value
+// from the empty set.
 func (s *ThreadSafeSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns nil and false.
+func (s *ThreadSafeSet) TryPop() (interface{}, bool) {
+	s.rw.Lock()
+	defer s.unlock()
+	for val := range s.set {
+		s.remove(val)
+		return val, true
+	}
+	return nil, false
+}
+
+// PopN removes at most n random values from the set and returns them. The
+// values are removed atomically, so no other goroutine can observe the set in
+// the middle of the PopN. If the set has less than n elements, it removes and
+// returns all of them.
+func (s *ThreadSafeSet) PopN(n uint) []interface{} {
+	s.rw.Lock()
+	defer s.unlock()
+	if n > s.size() {
+		n = s.size()
+	}
+	values := make([]interface{}, 0, n)
+	for val := range s.set {
+		if uint(len(values)) == n {
+			break
+		}
+		s.remove(val)
+		values = append(values, val)
+	}
+	return values
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns nil.
+func (s *ThreadSafeSet) Peek() interface{} {
 	s.rw.RLock()
 	defer s.rw.RUnlock()
 	for val := range s.set {
@@ -83,10 +142,84 @@ func (s *ThreadSafeSet) Pop() interface{} {
 // Clear removes everything from the set.
 func (s *ThreadSafeSet) Clear() {
 	s.rw.Lock()
-	defer s.rw.Unlock()
+	defer s.unlock()
+	s.clear()
+}
+
+// clear is the implementation of the Clear method which is not thread-safety.
+// It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeSet) clear() {
+	if s.watchers != nil {
+		for val := range s.set {
+			s.emit(ChangeRemoved, val)
+		}
+	}
 	s.set = make(map[interface{}]struct{})
 }
 
+// addNew adds the values and returns the ones which did not exist before. The
+// check and the addition are done under the same lock, so ObservableSet can
+// report the actual changes.
+func (s *ThreadSafeSet) addNew(values ...interface{}) []interface{} {
+	s.rw.Lock()
+	defer s.unlock()
+	var added []interface{}
+	for _, val := range values {
+		if !s.contains(val) {
+			s.add(val)
+			added = append(added, val)
+		}
+	}
+	return added
+}
+
+// removeExisting deletes the given value and returns true if it existed.
+func (s *ThreadSafeSet) removeExisting(val interface{}) bool {
+	s.rw.Lock()
+	defer s.unlock()
+	if !s.contains(val) {
+		return false
+	}
+	s.remove(val)
+	return true
+}
+
+// clearValues removes everything from the set and returns the removed values.
+func (s *ThreadSafeSet) clearValues() []interface{} {
+	s.rw.Lock()
+	defer s.unlock()
+	values := make([]interface{}, 0, len(s.set))
+	for val := range s.set {
+		values = append(values, val)
+	}
+	s.clear()
+	return values
+}
+
+// replace replaces the values of the set with the given values. Only the
+// missing values are removed, so the watchers receive the actual changes. It
+// also initializes the zero value, which is created by the decoders for the nil
+// pointers.
+func (s *ThreadSafeSet) replace(values []interface{}) {
+	s.rw.Lock()
+	defer s.unlock()
+	if s.set == nil {
+		s.set = make(map[interface{}]struct{}, len(values))
+	}
+	keep := make(map[interface{}]struct{}, len(values))
+	for _, val := range values {
+		keep[val] = setVal
+	}
+	for val := range s.set {
+		if _, ok := keep[val]; !ok {
+			s.remove(val)
+		}
+	}
+	for _, val := range values {
+		s.add(val)
+	}
+}
+
 // Empty checks whether the set is empty.
 func (s *ThreadSafeSet) Empty() bool {
 	s.rw.RLock()
@@ -109,81 +242,177 @@ func (s *ThreadSafeSet) Slice() []interface{} {
 	return values
 }
 
-// Union returns a new Set that contains all items from the receiver Set and
-// all items from the given Set.
-func (s *ThreadSafeSet) Union(set Set) Set {
-	o := set.(*ThreadSafeSet)
-
+// Each calls fn for every value in the set until fn returns false. The values
+// are visited in any order. The read lock is held during the iteration, so fn
+// must not modify the set.
+func (s *ThreadSafeSet) Each(fn func(val interface{}) bool) {
 	s.rw.RLock()
-	o.rw.RLock()
 	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	for val := range s.set {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set which can be used with
+// for range. The values are visited in any order. The read lock is held while
+// the loop runs, so the loop body must not modify the set.
+//
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ThreadSafeSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred. pred
+// is called under the read lock, so it must not modify the set.
+func (s *ThreadSafeSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, newThreadSafeSet(), pred)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. fn is called under the read lock, so it must not modify the set.
+func (s *ThreadSafeSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, newThreadSafeSet(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. The values are visited in any order. fn is
+// called under the read lock, so it must not modify the set.
+func (s *ThreadSafeSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the
+// values which satisfy pred and the second one contains the others. pred is
+// called under the read lock, so it must not modify the set.
+func (s *ThreadSafeSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, newThreadSafeSet(), newThreadSafeSet(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It
+// returns false for the empty set.
+func (s *ThreadSafeSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It returns true
+// for the empty set.
+func (s *ThreadSafeSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred.
+func (s *ThreadSafeSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// rLockBoth acquires the read locks of both mutexes in the order of their
+// addresses, so two goroutines which lock the same pair of sets in the opposite
+// order never wait for each other. If both mutexes are the same, it is locked
+// only once. It returns the function that releases the locks.
+func rLockBoth(a, b *sync.RWMutex) func() {
+	if a == b {
+		a.RLock()
+		return a.RUnlock
+	}
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.RLock()
+	b.RLock()
+	return func() {
+		b.RUnlock()
+		a.RUnlock()
+	}
+}
+
+// lockWith acquires the read locks which are needed for the binary operations
+// and returns the values of the given set with the function that releases the
+// locks. If the given set is a *ThreadSafeSet, its map is used directly under
+// its read lock. The locks are acquired by rLockBoth, so the lock order is the
+// same for every pair of sets and s.Union(s) does not lock s recursively.
+// Otherwise, the values of the given set are copied before s is locked, so the
+// given set's own locking never runs while s is locked. The returned map must
+// not be modified.
+func (s *ThreadSafeSet) lockWith(set Set) (map[interface{}]struct{}, func()) {
+	if o, ok := set.(*ThreadSafeSet); ok {
+		unlock := rLockBoth(&s.rw, &o.rw)
+		return o.set, unlock
+	}
+
+	values := set.Slice()
+	o := make(map[interface{}]struct{}, len(values))
+	for _, val := range values {
+		o[val] = setVal
+	}
+	s.rw.RLock()
+	return o, s.rw.RUnlock
+}
+
+// Union returns a new Set that contains all items from the receiver Set and
+// all items from the given Set. The given set can be any Set implementation and
+// the returned set is always a *ThreadSafeSet.
+func (s *ThreadSafeSet) Union(set Set) Set {
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
 	unionSet := newThreadSafeSet()
 	for val := range s.set {
 		unionSet.add(val)
 	}
-	for val := range o.set {
+	for val := range o {
 		unionSet.add(val)
 	}
 	return unionSet
 }
 
 // Intersection takes the common values from both sets and returns a new set
-// that stores the common ones.
+// that stores the common ones. The given set can be any Set implementation and
+// the returned set is always a *ThreadSafeSet.
 func (s *ThreadSafeSet) Intersection(set Set) Set {
-	o := set.(*ThreadSafeSet)
-
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
 	intersectSet := newThreadSafeSet()
-
 	for val := range s.set {
-		if o.contains(val) {
-			intersectSet.Add(val)
+		if _, ok := o[val]; ok {
+			intersectSet.add(val)
 		}
 	}
 	return intersectSet
 }
 
 // Difference takes the items that only is stored in s, receiver set. It returns
-// a new set.
+// a new set. The given set can be any Set implementation and the returned set
+// is always a *ThreadSafeSet.
 func (s *ThreadSafeSet) Difference(set Set) Set {
-	o := set.(*ThreadSafeSet)
-
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
 	diffSet := newThreadSafeSet()
 	for val := range s.set {
-		if !o.contains(val) {
-			diffSet.Add(val)
+		if _, ok := o[val]; !ok {
+			diffSet.add(val)
 		}
 	}
 	return diffSet
 }
 
 // IsSubset returns true if all items in the set exist in the given set.
-// Otherwise, it returns false.
+// Otherwise, it returns false. The given set can be any Set implementation.
 func (s *ThreadSafeSet) IsSubset(set Set) bool {
-	o := set.(*ThreadSafeSet)
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
-
-	if s.size() > o.size() {
+	if s.size() > uint(len(o)) {
 		return false
 	}
 
 	for val := range s.set {
-		if !o.contains(val) {
+		if _, ok := o[val]; !ok {
 			return false
 		}
 	}
@@ -191,20 +420,16 @@ func (s *ThreadSafeSet) IsSubset(set Set) bool {
 }
 
 // IsSuperset returns true if all items in the given set exist in the set.
-// Otherwise, it returns false.
+// Otherwise, it returns false. The given set can be any Set implementation.
 func (s *ThreadSafeSet) IsSuperset(set Set) bool {
-	o := set.(*ThreadSafeSet)
-
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
-	if s.size() < o.size() {
+	if s.size() < uint(len(o)) {
 		return false
 	}
 
-	for val := range o.set {
+	for val := range o {
 		if !s.contains(val) {
 			return false
 		}
@@ -212,41 +437,35 @@ func (s *ThreadSafeSet) IsSuperset(set Set) bool {
 	return true
 }
 
-// IsDisjoint returns true if none of the items are present in the sets.
+// IsDisjoint returns true if none of the items are present in the sets. The
+// given set can be any Set implementation.
 func (s *ThreadSafeSet) IsDisjoint(set Set) bool {
-	o := set.(*ThreadSafeSet)
-
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
-	if s.size() == 0 || o.size() == 0 {
+	if s.size() == 0 || len(o) == 0 {
 		return true
 	}
 	for val := range s.set {
-		if o.contains(val) {
+		if _, ok := o[val]; ok {
 			return false
 		}
 	}
 	return true
 }
 
-// Equal checks whether both sets contain exactly the same values.
+// Equal checks whether both sets contain exactly the same values. The given
+// set can be any Set implementation.
 func (s *ThreadSafeSet) Equal(set Set) bool {
-	o := set.(*ThreadSafeSet)
-
-	s.rw.RLock()
-	o.rw.RLock()
-	defer s.rw.RUnlock()
-	defer o.rw.RUnlock()
+	o, unlock := s.lockWith(set)
+	defer unlock()
 
-	if s.size() != o.size() {
+	if s.size() != uint(len(o)) {
 		return false
 	}
 
 	for val := range s.set {
-		if !o.contains(val) {
+		if _, ok := o[val]; !ok {
 			return false
 		}
 	}
@@ -254,8 +473,24 @@ func (s *ThreadSafeSet) Equal(set Set) bool {
 }
 
 // SymmetricDifference returns a set that contains from two sets, but not the
-// items are present in both sets.
+// items are present in both sets. The given set can be any Set
+// implementation and the returned set is always a *ThreadSafeSet. Both
+// sets are read under the same locks, so the result is consistent even if the
+// sets are modified concurrently.
 func (s *ThreadSafeSet) SymmetricDifference(set Set) Set {
-	o := set.(*ThreadSafeSet)
-	return s.Difference(o).Union(o.Difference(s))
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	symDiffSet := newThreadSafeSet()
+	for val := range s.set {
+		if _, ok := o[val]; !ok {
+			symDiffSet.add(val)
+		}
+	}
+	for val := range o {
+		if !s.contains(val) {
+			symDiffSet.add(val)
+		}
+	}
+	return symDiffSet
 }
@@ -0,0 +1,49 @@
+package set
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// benchmarkParallelAdd adds distinct values into s from the parallel
+// goroutines.
+func benchmarkParallelAdd(b *testing.B, s Set) {
+	var next atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Add(next.Add(1))
+		}
+	})
+}
+
+func BenchmarkThreadSafeSet_ParallelAdd(b *testing.B) {
+	benchmarkParallelAdd(b, newThreadSafeSet())
+}
+
+func BenchmarkShardedSet_ParallelAdd(b *testing.B) {
+	benchmarkParallelAdd(b, New(Sharded))
+}
+
+// benchmarkParallelContains checks the values of s from the parallel
+// goroutines.
+func benchmarkParallelContains(b *testing.B, s Set) {
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Contains(i % 1000)
+			i++
+		}
+	})
+}
+
+func BenchmarkThreadSafeSet_ParallelContains(b *testing.B) {
+	benchmarkParallelContains(b, newThreadSafeSet())
+}
+
+func BenchmarkCopyOnWriteSet_ParallelContains(b *testing.B) {
+	benchmarkParallelContains(b, NewCopyOnWrite())
+}
@@ -0,0 +1,162 @@
+package set
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// stressTimeout is the duration after which a stress test is considered as
+// deadlocked.
+const stressTimeout = 30 * time.Second
+
+// runStress runs every function in fns concurrently for n iterations and fails
+// the test if they do not finish before stressTimeout.
+func runStress(t *testing.T, n int, fns ...func(i int)) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		wg.Add(1)
+		go func(fn func(i int)) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				fn(i)
+			}
+		}(fn)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stressTimeout):
+		t.Fatalf("stress test did not finish in %v, possible deadlock", stressTimeout)
+	}
+}
+
+func TestThreadSafeSet_StressOppositeOrder(t *testing.T) {
+	a := newThreadSafeSet()
+	b := newThreadSafeSet()
+	runStress(t, 2000,
+		func(i int) { a.Union(b) },
+		func(i int) { b.Intersection(a) },
+		func(i int) { a.SymmetricDifference(b) },
+		func(i int) { b.IsSubset(a) },
+		func(i int) { a.Equal(b) },
+		func(i int) { b.Difference(a) },
+		func(i int) { a.Add(i) },
+		func(i int) { b.Add(i) },
+		func(i int) { a.Remove(i - 1) },
+		func(i int) { b.Clear() },
+	)
+}
+
+func TestThreadSafeSet_StressSelf(t *testing.T) {
+	s := newThreadSafeSet()
+	runStress(t, 2000,
+		func(i int) { s.Union(s) },
+		func(i int) { s.Intersection(s) },
+		func(i int) { s.SymmetricDifference(s) },
+		func(i int) { s.IsSuperset(s) },
+		func(i int) { s.IsDisjoint(s) },
+		func(i int) { s.Add(i) },
+		func(i int) { s.Remove(i) },
+	)
+}
+
+func TestThreadSafeSet_StressMixed(t *testing.T) {
+	a := newThreadSafeSet()
+	b := newThreadUnsafeSet()
+	b.Append(1, 2, 3)
+	f := foreignSet{a}
+	runStress(t, 2000,
+		func(i int) { a.Union(b) },
+		func(i int) { a.Union(f) },
+		func(i int) { a.SymmetricDifference(f) },
+		func(i int) { a.Add(i) },
+	)
+}
+
+func TestThreadSafeTypedSet_StressOppositeOrder(t *testing.T) {
+	a := newThreadSafeTypedSet[int]()
+	b := newThreadSafeTypedSet[int]()
+	runStress(t, 2000,
+		func(i int) { a.Union(b) },
+		func(i int) { b.Intersection(a) },
+		func(i int) { a.SymmetricDifference(b) },
+		func(i int) { a.Union(a) },
+		func(i int) { a.Add(i) },
+		func(i int) { b.Add(i) },
+		func(i int) { b.Clear() },
+	)
+}
+
+func TestThreadSafeSet_SymmetricDifferenceSelf(t *testing.T) {
+	s := newThreadSafeSet()
+	s.Append(1, 2, 3)
+	if symDiff := s.SymmetricDifference(s); !symDiff.Empty() {
+		t.Errorf("expected empty set, actual size %v", symDiff.Size())
+	}
+	if union := s.Union(s); union.Size() != 3 {
+		t.Errorf("expected size 3, actual size %v", union.Size())
+	}
+}
+
+func TestShardedSet_Stress(t *testing.T) {
+	a := NewSharded(16)
+	b := New(ThreadSafe)
+	runStress(t, 2000,
+		func(i int) { a.Add(i) },
+		func(i int) { a.Add(-i) },
+		func(i int) { a.Remove(i - 1) },
+		func(i int) { b.Add(i) },
+		func(i int) { a.Union(b) },
+		func(i int) { b.Intersection(a) },
+		func(i int) { a.Union(a) },
+		func(i int) { a.Equal(b) },
+		func(i int) { a.IsSuperset(a) },
+		func(i int) { a.Slice() },
+		func(i int) { a.Size() },
+		func(i int) { a.TryPop() },
+		func(i int) {
+			if i%100 == 0 {
+				a.Clear()
+			}
+		},
+	)
+
+	if n := uint(len(a.Slice())); a.Size() != n {
+		t.Errorf("expected size %v, actual %v", n, a.Size())
+	}
+}
+
+func TestCopyOnWriteSet_Stress(t *testing.T) {
+	a := NewCopyOnWrite()
+	b := New(ThreadSafe)
+	runStress(t, 1000,
+		func(i int) { a.Add(i) },
+		func(i int) { a.Append(-i, -i-1) },
+		func(i int) { a.Remove(i - 1) },
+		func(i int) { a.Contains(i) },
+		func(i int) { b.Add(i) },
+		func(i int) { a.Union(b) },
+		func(i int) { b.Intersection(a) },
+		func(i int) { a.SymmetricDifference(a) },
+		func(i int) { a.Equal(b) },
+		func(i int) { a.TryPop() },
+		func(i int) {
+			a.Each(func(val interface{}) bool {
+				a.Contains(val)
+				return true
+			})
+		},
+		func(i int) {
+			if i%100 == 0 {
+				a.Clear()
+			}
+		},
+	)
+}
@@ -275,7 +275,7 @@ func TestThreadSafeSet_Pop(t *testing.T) {
 		{
 			name:    "Pop from a non-empty set",
 			values:  []interface{}{1, 2, 3, 4.53, "str", "set", true, 'b'},
-			expSize: 8,
+			expSize: 7,
 		},
 	}
 
@@ -285,15 +285,122 @@ func TestThreadSafeSet_Pop(t *testing.T) {
 			s.Append(tc.values...)
 
 			val := s.Pop()
-			if s.Size() == 0 && val != nil { // empty set
+			if len(tc.values) == 0 && val != nil { // empty set
 				t.Errorf("expected popped value is nil, actual %v", val)
-			} else if s.Size() > 0 && !s.Contains(val) { // non-empty set
-				t.Errorf("set should contain %v, but not contain", val)
+			} else if len(tc.values) > 0 && s.Contains(val) { // non-empty set
+				t.Errorf("set should not contain %v after pop, but contains", val)
+			}
+			if s.Size() != tc.expSize {
+				t.Errorf("expected size %v, actual size %v", tc.expSize, s.Size())
 			}
 		})
 	}
 }
 
+func TestThreadSafeSet_TryPop(t *testing.T) {
+	s := newThreadSafeSet()
+	if val, ok := s.TryPop(); ok || val != nil {
+		t.Errorf("expected nil and false, actual %v and %v", val, ok)
+	}
+
+	s.Add(nil)
+	if val, ok := s.TryPop(); !ok || val != nil {
+		t.Errorf("expected nil and true, actual %v and %v", val, ok)
+	}
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
+
+func TestThreadSafeSet_PopN(t *testing.T) {
+	testCases := []struct {
+		name    string
+		values  []interface{}
+		n       uint
+		expLen  int
+		expSize uint
+	}{
+		{
+			name: "PopN from empty set",
+			n:    3,
+		},
+		{
+			name:    "PopN less than size",
+			values:  []interface{}{1, 2, 3, 4, 5},
+			n:       3,
+			expLen:  3,
+			expSize: 2,
+		},
+		{
+			name:   "PopN more than size",
+			values: []interface{}{1, 2, 3},
+			n:      5,
+			expLen: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadSafeSet()
+			s.Append(tc.values...)
+
+			values := s.PopN(tc.n)
+			if len(values) != tc.expLen {
+				t.Errorf("expected length %v, actual length %v", tc.expLen, len(values))
+			}
+			if s.Size() != tc.expSize {
+				t.Errorf("expected size %v, actual size %v", tc.expSize, s.Size())
+			}
+			for _, val := range values {
+				if s.Contains(val) {
+					t.Errorf("%v is popped, but still exists", val)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadSafeSet_PopNConcurrent(t *testing.T) {
+	s := newThreadSafeSet()
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+	}
+
+	popped := make(chan []interface{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			popped <- s.PopN(100)
+		}()
+	}
+
+	seen := make(map[interface{}]struct{})
+	for i := 0; i < 10; i++ {
+		for _, val := range <-popped {
+			if _, ok := seen[val]; ok {
+				t.Errorf("%v is popped more than once", val)
+			}
+			seen[val] = setVal
+		}
+	}
+	if len(seen) != 1000 || !s.Empty() {
+		t.Errorf("expected all values are popped, actual %v", len(seen))
+	}
+}
+
+func TestThreadSafeSet_Peek(t *testing.T) {
+	s := newThreadSafeSet()
+	if val := s.Peek(); val != nil {
+		t.Errorf("expected nil, actual %v", val)
+	}
+	s.Append(1, 2, 3)
+	if val := s.Peek(); !s.Contains(val) {
+		t.Errorf("%v not exist in set", val)
+	}
+	if s.Size() != 3 {
+		t.Errorf("expected size 3, actual size %v", s.Size())
+	}
+}
+
 func TestThreadSafeSet_Clear(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -929,3 +1036,77 @@ func TestThreadSafeSet_SymmetricDifference(t *testing.T) {
 		})
 	}
 }
+
+func TestThreadSafeSet_Each(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		stop     int
+		expCalls int
+	}{
+		{
+			name: "Each on empty set",
+			stop: 1,
+		},
+		{
+			name:     "Each visits all values",
+			values:   []interface{}{1, "str", 2.5, true},
+			stop:     10,
+			expCalls: 4,
+		},
+		{
+			name:     "Each stops early",
+			values:   []interface{}{1, 2, 3, 4, 5},
+			stop:     2,
+			expCalls: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadSafeSet()
+			s.Append(tc.values...)
+
+			values := make(map[interface{}]struct{})
+			for _, val := range tc.values {
+				values[val] = setVal
+			}
+
+			calls := 0
+			s.Each(func(val interface{}) bool {
+				if _, ok := values[val]; !ok {
+					t.Errorf("%v not exist in set", val)
+				}
+				calls++
+				return calls < tc.stop
+			})
+			if calls != tc.expCalls {
+				t.Errorf("expected %v calls, actual %v calls", tc.expCalls, calls)
+			}
+		})
+	}
+}
+
+func TestThreadSafeSet_All(t *testing.T) {
+	s := newThreadSafeSet()
+	s.Append(1, 2, 3, 4)
+
+	seen := make(map[interface{}]struct{})
+	for val := range s.All() {
+		seen[val] = setVal
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected 4 values, actual %v values", len(seen))
+	}
+
+	count := 0
+	for range s.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected loop breaks at 2, actual %v", count)
+	}
+}
@@ -1,5 +1,7 @@
 package set
 
+import "iter"
+
 // ThreadUnsafeSet is a set type which does not provide the thread-safety.
 type ThreadUnsafeSet struct {
 	set map[interface{}]struct{}
@@ -10,8 +12,9 @@ func newThreadUnsafeSet() *ThreadUnsafeSet {
 	return &ThreadUnsafeSet{set: make(map[interface{}]struct{})}
 }
 
-// Add adds a new values to set if there is enough capacity. It is not a
-// thread-safe method. It does not handle the concurrency.
+// Add adds a new values to set. The set has no capacity limit; NewBounded
+// creates a set with a maximum size. It is not a thread-safe method. It does
+// not handle the concurrency.
 //
 // Example:
 //	s.Add("str")
@@ -59,12 +62,60 @@ func (s ThreadUnsafeSet) Size() uint {
 	return uint(len(s.set))
 }
 
-// Pop returns a random value from the set. If there is no element in set, it
-// returns nil. It is not a thread-safe method. It does not handle the concurrency.
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns nil. Use TryPop for distinguishing a nil value
+// from the empty set. It is not a thread-safe method. It does not handle the
+// concurrency.
 //
 // Example:
 //	val := s.Pop()
-func (s ThreadUnsafeSet) Pop() interface{} {
+func (s *ThreadUnsafeSet) Pop() interface{} {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns nil and false. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	val, ok := s.TryPop()
+func (s *ThreadUnsafeSet) TryPop() (interface{}, bool) {
+	for val := range s.set {
+		delete(s.set, val)
+		return val, true
+	}
+	return nil, false
+}
+
+// PopN removes at most n random values from the set and returns them. If the
+// set has less than n elements, it removes and returns all of them. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	values := s.PopN(10)
+func (s *ThreadUnsafeSet) PopN(n uint) []interface{} {
+	if n > s.Size() {
+		n = s.Size()
+	}
+	values := make([]interface{}, 0, n)
+	for val := range s.set {
+		if uint(len(values)) == n {
+			break
+		}
+		delete(s.set, val)
+		values = append(values, val)
+	}
+	return values
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns nil. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	val := s.Peek()
+func (s ThreadUnsafeSet) Peek() interface{} {
 	for val := range s.set {
 		return val
 	}
@@ -80,6 +131,12 @@ func (s *ThreadUnsafeSet) Clear() {
 	s.set = make(map[interface{}]struct{})
 }
 
+// replace replaces the values of the set with the given values.
+func (s *ThreadUnsafeSet) replace(values []interface{}) {
+	s.set = make(map[interface{}]struct{}, len(values))
+	s.Append(values...)
+}
+
 // Empty checks whether the set is empty. It is not a thread-safe method. It does
 // not handle the concurrency.
 //
@@ -106,27 +163,129 @@ func (s ThreadUnsafeSet) Slice() []interface{} {
 	return values
 }
 
-// Union returns a new Set that contains all items from the receiver Set and
-// all items from the given Set. It is not a thread-safe method. It does  not
+// Each calls fn for every value in the set until fn returns false. The values
+// are visited in any order. Removing values from fn is safe, but the values
+// added by fn may or may not be visited. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	s.Each(func(val interface{}) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *ThreadUnsafeSet) Each(fn func(val interface{}) bool) {
+	for val := range s.set {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set which can be used with
+// for range. It follows the same rules with Each. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ThreadUnsafeSet) All() iter.Seq[interface{}] {
+	return s.Each
+}
+
+// Filter returns a new set that contains the values which satisfy pred. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens := s.Filter(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeSet) Filter(pred func(val interface{}) bool) Set {
+	return filter(s, newThreadUnsafeSet(), pred)
+}
+
+// Map returns a new set that contains the results of fn for every value in the
+// set. It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	doubles := s.Map(func(val interface{}) interface{} { return val.(int) * 2 })
+func (s *ThreadUnsafeSet) Map(fn func(val interface{}) interface{}) Set {
+	return mapTo(s, newThreadUnsafeSet(), fn)
+}
+
+// Reduce folds the values of the set into a single value by calling fn for
+// every value, starting with init. The values are visited in any order. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	sum := s.Reduce(0, func(acc, val interface{}) interface{} { return acc.(int) + val.(int) })
+func (s *ThreadUnsafeSet) Reduce(init interface{}, fn func(acc, val interface{}) interface{}) interface{} {
+	return reduce(s, init, fn)
+}
+
+// Partition splits the set into two new sets. The first one contains the
+// values which satisfy pred and the second one contains the others. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evens, odds := s.Partition(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeSet) Partition(pred func(val interface{}) bool) (Set, Set) {
+	return partition(s, newThreadUnsafeSet(), newThreadUnsafeSet(), pred)
+}
+
+// AnyMatch returns true if at least one value in the set satisfies pred. It
+// returns false for the empty set. It is not a thread-safe method. It does not
 // handle the concurrency.
 //
 // Example:
+//	hasNegative := s.AnyMatch(func(val interface{}) bool { return val.(int) < 0 })
+func (s *ThreadUnsafeSet) AnyMatch(pred func(val interface{}) bool) bool {
+	return anyMatch(s, pred)
+}
+
+// AllMatch returns true if all values in the set satisfy pred. It returns true
+// for the empty set. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	allPositive := s.AllMatch(func(val interface{}) bool { return val.(int) > 0 })
+func (s *ThreadUnsafeSet) AllMatch(pred func(val interface{}) bool) bool {
+	return allMatch(s, pred)
+}
+
+// CountIf returns the number of values in the set which satisfy pred. It is not
+// a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	evenCount := s.CountIf(func(val interface{}) bool { return val.(int)%2 == 0 })
+func (s *ThreadUnsafeSet) CountIf(pred func(val interface{}) bool) uint {
+	return countIf(s, pred)
+}
+
+// Union returns a new Set that contains all items from the receiver Set and
+// all items from the given Set. The given set can be any Set implementation and
+// the returned set is always a *ThreadUnsafeSet. It is not a thread-safe
+// method. It does  not handle the concurrency.
+//
+// Example:
 //	unionSet := s1.Union(s2)
 func (s ThreadUnsafeSet) Union(set Set) Set {
-	o := set.(*ThreadUnsafeSet)
-	unionSet := New(ThreadUnsafe)
+	unionSet := newThreadUnsafeSet()
 	for val := range s.set {
 		unionSet.Add(val)
 	}
-	for val := range o.set {
-		unionSet.Add(val)
+	if o, ok := set.(*ThreadUnsafeSet); ok {
+		for val := range o.set {
+			unionSet.Add(val)
+		}
+	} else {
+		unionSet.Append(set.Slice()...)
 	}
 	return unionSet
 }
 
 // Intersection takes the common values from both sets and returns a new set
-// that stores the common ones. It is not a thread-safe method. It does  not
-// handle the concurrency.
+// that stores the common ones. The given set can be any Set implementation and
+// the returned set is always a *ThreadUnsafeSet. It is not a thread-safe
+// method. It does  not handle the concurrency.
 //
 // Example:
 //	intersectionSet := s1.Intersection(s2)
@@ -141,15 +300,16 @@ func (s *ThreadUnsafeSet) Intersection(set Set) Set {
 }
 
 // Difference takes the items that only is stored in s, receiver set. It returns
-// a new set. It is not a thread-safe method. It does not handle the concurrency.
+// a new set. The given set can be any Set implementation and the returned set is
+// always a *ThreadUnsafeSet. It is not a thread-safe method. It does not handle
+// the concurrency.
 //
 // Example:
 //	diffSet := s1.Difference(s2)
 func (s *ThreadUnsafeSet) Difference(set Set) Set {
-	o := set.(*ThreadUnsafeSet)
 	diffSet := newThreadUnsafeSet()
 	for val := range s.set {
-		if !o.Contains(val) {
+		if !set.Contains(val) {
 			diffSet.Add(val)
 		}
 	}
@@ -176,18 +336,29 @@ func (s *ThreadUnsafeSet) IsSubset(set Set) bool {
 }
 
 // IsSuperset returns true if all items in the given set exist in the set.
-// Otherwise, it returns false. It is not a thread-safe method. It does not
-// handle the concurrency.
+// Otherwise, it returns false. The given set can be any Set implementation. It
+// is not a thread-safe method. It does not handle the concurrency.
 //
 // Example:
 //	isSuperset := s1.IsSuperset(s2)
 func (s *ThreadUnsafeSet) IsSuperset(set Set) bool {
-	o := set.(*ThreadUnsafeSet)
-	if s.Size() < set.Size() {
-		return false
+	if o, ok := set.(*ThreadUnsafeSet); ok {
+		if s.Size() < o.Size() {
+			return false
+		}
+		for val := range o.set {
+			if !s.Contains(val) {
+				return false
+			}
+		}
+		return true
 	}
 
-	for val := range o.set {
+	values := set.Slice()
+	if s.Size() < uint(len(values)) {
+		return false
+	}
+	for _, val := range values {
 		if !s.Contains(val) {
 			return false
 		}
@@ -231,8 +402,9 @@ func (s *ThreadUnsafeSet) Equal(set Set) bool {
 }
 
 // SymmetricDifference returns a set that contains from two sets, but not the
-// items are present in both sets. It is not a thread-safe method. It does not
-// handle the concurrency.
+// items are present in both sets. The given set can be any Set implementation
+// and the returned set is always a *ThreadUnsafeSet. It is not a thread-safe
+// method. It does not handle the concurrency.
 //
 // Example:
 //	symmetricDiffSet := s1.SymmetricDifference(s2)
@@ -41,3 +41,22 @@ func BenchmarkThreadUnsafeSet_Contains(b *testing.B) {
 		s.Contains(i)
 	}
 }
+
+func BenchmarkBitSet_Add(b *testing.B) {
+	s := NewBitSet()
+	for i := 0; i < b.N; i++ {
+		s.AddUint(uint(i))
+	}
+}
+
+func BenchmarkBitSet_Contains(b *testing.B) {
+	b.StopTimer()
+	s := NewBitSet()
+	for i := 0; i < b.N; i++ {
+		s.AddUint(uint(i))
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		s.ContainsUint(uint(i))
+	}
+}
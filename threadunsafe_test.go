@@ -376,6 +376,12 @@ func TestThreadUnsafeSet_Pop(t *testing.T) {
 				tc.set.Append(tc.values...)
 			}
 			value := tc.set.Pop()
+			if tc.set.Contains(value) {
+				t.Errorf("%v is popped, but still exists", value)
+			}
+			if !tc.isEmpty && tc.set.Size() != uint(len(tc.values)-1) {
+				t.Errorf("expected size %v, actual size %v", len(tc.values)-1, tc.set.Size())
+			}
 			for _, val := range tc.values {
 				if val == value {
 					return
@@ -391,6 +397,83 @@ func TestThreadUnsafeSet_Pop(t *testing.T) {
 	}
 }
 
+func TestThreadUnsafeSet_TryPop(t *testing.T) {
+	s := newThreadUnsafeSet()
+	if val, ok := s.TryPop(); ok || val != nil {
+		t.Errorf("expected nil and false, actual %v and %v", val, ok)
+	}
+
+	s.Add(nil)
+	if val, ok := s.TryPop(); !ok || val != nil {
+		t.Errorf("expected nil and true, actual %v and %v", val, ok)
+	}
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
+
+func TestThreadUnsafeSet_PopN(t *testing.T) {
+	testCases := []struct {
+		name    string
+		values  []interface{}
+		n       uint
+		expLen  int
+		expSize uint
+	}{
+		{
+			name: "PopN from empty set",
+			n:    3,
+		},
+		{
+			name:    "PopN less than size",
+			values:  []interface{}{1, 2, 3, 4, 5},
+			n:       3,
+			expLen:  3,
+			expSize: 2,
+		},
+		{
+			name:   "PopN more than size",
+			values: []interface{}{1, 2, 3},
+			n:      5,
+			expLen: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadUnsafeSet()
+			s.Append(tc.values...)
+
+			values := s.PopN(tc.n)
+			if len(values) != tc.expLen {
+				t.Errorf("expected length %v, actual length %v", tc.expLen, len(values))
+			}
+			if s.Size() != tc.expSize {
+				t.Errorf("expected size %v, actual size %v", tc.expSize, s.Size())
+			}
+			for _, val := range values {
+				if s.Contains(val) {
+					t.Errorf("%v is popped, but still exists", val)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadUnsafeSet_Peek(t *testing.T) {
+	s := newThreadUnsafeSet()
+	if val := s.Peek(); val != nil {
+		t.Errorf("expected nil, actual %v", val)
+	}
+	s.Append(1, 2, 3)
+	if val := s.Peek(); !s.Contains(val) {
+		t.Errorf("%v not exist in set", val)
+	}
+	if s.Size() != 3 {
+		t.Errorf("expected size 3, actual size %v", s.Size())
+	}
+}
+
 func TestThreadUnsafeSet_Clear(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -1045,3 +1128,89 @@ func TestThreadUnsafeSet_SymmetricDifference(t *testing.T) {
 		})
 	}
 }
+
+func TestThreadUnsafeSet_Each(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []interface{}
+		stop     int
+		expCalls int
+	}{
+		{
+			name: "Each on empty set",
+			stop: 1,
+		},
+		{
+			name:     "Each visits all values",
+			values:   []interface{}{1, "str", 2.5, true},
+			stop:     10,
+			expCalls: 4,
+		},
+		{
+			name:     "Each stops early",
+			values:   []interface{}{1, 2, 3, 4, 5},
+			stop:     2,
+			expCalls: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadUnsafeSet()
+			s.Append(tc.values...)
+
+			values := make(map[interface{}]struct{})
+			for _, val := range tc.values {
+				values[val] = setVal
+			}
+
+			calls := 0
+			s.Each(func(val interface{}) bool {
+				if _, ok := values[val]; !ok {
+					t.Errorf("%v not exist in set", val)
+				}
+				calls++
+				return calls < tc.stop
+			})
+			if calls != tc.expCalls {
+				t.Errorf("expected %v calls, actual %v calls", tc.expCalls, calls)
+			}
+		})
+	}
+}
+
+func TestThreadUnsafeSet_All(t *testing.T) {
+	s := newThreadUnsafeSet()
+	s.Append(1, 2, 3, 4)
+
+	seen := make(map[interface{}]struct{})
+	for val := range s.All() {
+		seen[val] = setVal
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected 4 values, actual %v values", len(seen))
+	}
+
+	count := 0
+	for range s.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected loop breaks at 2, actual %v", count)
+	}
+}
+
+func TestThreadUnsafeSet_EachRemove(t *testing.T) {
+	s := newThreadUnsafeSet()
+	s.Append(1, 2, 3, 4)
+	s.Each(func(val interface{}) bool {
+		s.Remove(val)
+		return true
+	})
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
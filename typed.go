@@ -0,0 +1,104 @@
+package set
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrTypeMismatch is returned when a value stored in a Set cannot be converted
+// into the element type of a TypedSet.
+var ErrTypeMismatch = errors.New("set: element type mismatch")
+
+// TypedSet is the generic version of the Set interface. Values are stored with
+// their own type, so there is no need for type assertion while reading them. Like
+// Set, the binary operations accept any TypedSet implementation and the sets
+// returned from them have the same kind as the receiver set. Each and All follow
+// the same iteration rules with Set.
+type TypedSet[T comparable] interface {
+	Add(val T)
+	Append(val ...T)
+	Remove(val T)
+	Contains(val T) bool
+	Size() uint
+	Pop() T
+	TryPop() (T, bool)
+	Peek() T
+	Clear()
+	Empty() bool
+	Slice() []T
+	Each(fn func(val T) bool)
+	All() iter.Seq[T]
+	Union(set TypedSet[T]) TypedSet[T]
+	Intersection(set TypedSet[T]) TypedSet[T]
+	Difference(set TypedSet[T]) TypedSet[T]
+	IsSubset(set TypedSet[T]) bool
+	IsSuperset(set TypedSet[T]) bool
+	IsDisjoint(set TypedSet[T]) bool
+	Equal(set TypedSet[T]) bool
+	SymmetricDifference(set TypedSet[T]) TypedSet[T]
+}
+
+// NewTyped creates a generic set data structure regarding setType. It works
+// like New, but the returned set only accepts the values of type T.
+//
+//	safeSet := NewTyped[int](set.ThreadSafe)	// Creates a thread-safe set of ints.
+//	unsafeSet := NewTyped[string](set.ThreadUnsafe)	// Creates a thread-unsafe set of strings.
+func NewTyped[T comparable](t setType) TypedSet[T] {
+	var set TypedSet[T]
+	switch t {
+	case ThreadSafe:
+		set = newThreadSafeTypedSet[T]()
+	case ThreadUnsafe:
+		set = newThreadUnsafeTypedSet[T]()
+	}
+	return set
+}
+
+// NewTypedOf creates a generic set regarding setType like NewTyped and adds the
+// given values into it. The Go syntax of the typed sets, which is printed by
+// %#v, uses NewTypedOf.
+//
+//	s := set.NewTypedOf(set.ThreadUnsafe, 1, 2, 3)	// Creates a set of ints.
+func NewTypedOf[T comparable](t setType, values ...T) TypedSet[T] {
+	set := NewTyped[T](t)
+	set.Append(values...)
+	return set
+}
+
+// ToSet copies the values of the given TypedSet into a new Set. The returned
+// set is thread-safe if the given set is thread-safe.
+//
+//	s := set.ToSet[int](typedSet)
+func ToSet[T comparable](ts TypedSet[T]) Set {
+	t := ThreadUnsafe
+	if _, ok := ts.(*ThreadSafeTypedSet[T]); ok {
+		t = ThreadSafe
+	}
+	s := New(setType(t))
+	for _, val := range ts.Slice() {
+		s.Add(val)
+	}
+	return s
+}
+
+// FromSet copies the values of the given Set into a new TypedSet. The returned
+// set is thread-safe if the given set is thread-safe. It returns an error that
+// wraps ErrTypeMismatch if any value in the set is not of type T.
+//
+//	ts, err := set.FromSet[int](s)
+func FromSet[T comparable](s Set) (TypedSet[T], error) {
+	t := ThreadUnsafe
+	if _, ok := s.(*ThreadSafeSet); ok {
+		t = ThreadSafe
+	}
+	ts := NewTyped[T](setType(t))
+	for _, val := range s.Slice() {
+		v, ok := val.(T)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is %T", ErrTypeMismatch, val, val)
+		}
+		ts.Add(v)
+	}
+	return ts, nil
+}
@@ -0,0 +1,177 @@
+package set
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewTyped(t *testing.T) {
+	if _, ok := NewTyped[int](ThreadSafe).(*ThreadSafeTypedSet[int]); !ok {
+		t.Errorf("expected *ThreadSafeTypedSet[int]")
+	}
+	if _, ok := NewTyped[string](ThreadUnsafe).(*ThreadUnsafeTypedSet[string]); !ok {
+		t.Errorf("expected *ThreadUnsafeTypedSet[string]")
+	}
+}
+
+func TestToSet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		setType  setType
+		values   []int
+		expSafe  bool
+		expValue []interface{}
+	}{
+		{
+			name:    "Empty thread-safe set",
+			setType: ThreadSafe,
+			expSafe: true,
+		},
+		{
+			name:     "Thread-safe set",
+			setType:  ThreadSafe,
+			values:   []int{1, 2, 3},
+			expSafe:  true,
+			expValue: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "Thread-unsafe set",
+			setType:  ThreadUnsafe,
+			values:   []int{1, 2, 3},
+			expValue: []interface{}{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := NewTyped[int](tc.setType)
+			ts.Append(tc.values...)
+
+			s := ToSet(ts)
+			if _, ok := s.(*ThreadSafeSet); ok != tc.expSafe {
+				t.Errorf("expected thread-safe %v, actual %T", tc.expSafe, s)
+			}
+			if s.Size() != uint(len(tc.expValue)) {
+				t.Errorf("expected size %v, actual size %v", len(tc.expValue), s.Size())
+			}
+			for _, val := range tc.expValue {
+				if !s.Contains(val) {
+					t.Errorf("%v not exist in set", val)
+				}
+			}
+		})
+	}
+}
+
+func TestFromSet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		set     Set
+		values  []interface{}
+		expSafe bool
+		expErr  error
+	}{
+		{
+			name:    "Empty thread-safe set",
+			set:     New(ThreadSafe),
+			expSafe: true,
+		},
+		{
+			name:    "Thread-safe set",
+			set:     New(ThreadSafe),
+			values:  []interface{}{"a", "b", "c"},
+			expSafe: true,
+		},
+		{
+			name:   "Thread-unsafe set",
+			set:    New(ThreadUnsafe),
+			values: []interface{}{"a", "b", "c"},
+		},
+		{
+			name:   "Mixed types",
+			set:    New(ThreadUnsafe),
+			values: []interface{}{"a", 1},
+			expErr: ErrTypeMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.set.Append(tc.values...)
+
+			ts, err := FromSet[string](tc.set)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected error %v, actual error %v", tc.expErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := ts.(*ThreadSafeTypedSet[string]); ok != tc.expSafe {
+				t.Errorf("expected thread-safe %v, actual %T", tc.expSafe, ts)
+			}
+			if ts.Size() != tc.set.Size() {
+				t.Errorf("expected size %v, actual size %v", tc.set.Size(), ts.Size())
+			}
+			for _, val := range tc.values {
+				if !ts.Contains(val.(string)) {
+					t.Errorf("%v not exist in set", val)
+				}
+			}
+		})
+	}
+}
+
+// checkTypedSet checks whether s contains exactly the expected values.
+func checkTypedSet(t *testing.T, name string, s TypedSet[int], exp []int) {
+	t.Helper()
+	if s.Size() != uint(len(exp)) {
+		t.Errorf("%s: expected size %v, actual size %v", name, len(exp), s.Size())
+	}
+	for _, val := range exp {
+		if !s.Contains(val) {
+			t.Errorf("%s: expected %v, but not exists", name, val)
+		}
+	}
+}
+
+func TestTypedSet_MixedOperations(t *testing.T) {
+	testCases := []struct {
+		name string
+		set1 TypedSet[int]
+		set2 TypedSet[int]
+	}{
+		{
+			name: "Thread-safe and thread-unsafe",
+			set1: NewTyped[int](ThreadSafe),
+			set2: NewTyped[int](ThreadUnsafe),
+		},
+		{
+			name: "Thread-unsafe and thread-safe",
+			set1: NewTyped[int](ThreadUnsafe),
+			set2: NewTyped[int](ThreadSafe),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.set1.Append(1, 2, 3)
+			tc.set2.Append(2, 3, 4)
+
+			checkTypedSet(t, "union", tc.set1.Union(tc.set2), []int{1, 2, 3, 4})
+			checkTypedSet(t, "intersection", tc.set1.Intersection(tc.set2), []int{2, 3})
+			checkTypedSet(t, "difference", tc.set1.Difference(tc.set2), []int{1})
+			checkTypedSet(t, "symmetric difference", tc.set1.SymmetricDifference(tc.set2), []int{1, 4})
+			if tc.set1.IsSuperset(tc.set2) || tc.set1.IsSubset(tc.set2) || tc.set1.Equal(tc.set2) {
+				t.Errorf("expected different sets")
+			}
+			if _, ok := tc.set1.Union(tc.set2).(*ThreadSafeTypedSet[int]); ok != isThreadSafeTyped(tc.set1) {
+				t.Errorf("expected the kind of the receiver set")
+			}
+		})
+	}
+}
+
+func isThreadSafeTyped(s TypedSet[int]) bool {
+	_, ok := s.(*ThreadSafeTypedSet[int])
+	return ok
+}
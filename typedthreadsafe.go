@@ -0,0 +1,346 @@
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// ThreadSafeTypedSet is the generic version of ThreadSafeSet. It provides the
+// thread-safety.
+type ThreadSafeTypedSet[T comparable] struct {
+	set map[T]struct{}
+	rw  sync.RWMutex
+}
+
+// newThreadSafeTypedSet creates a new *ThreadSafeTypedSet.
+func newThreadSafeTypedSet[T comparable]() *ThreadSafeTypedSet[T] {
+	return &ThreadSafeTypedSet[T]{set: make(map[T]struct{})}
+}
+
+// Add adds a new values to set.
+func (s *ThreadSafeTypedSet[T]) Add(val T) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.add(val)
+}
+
+// add is the implementation of the Add method which is not thread-safety.
+// It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeTypedSet[T]) add(val T) {
+	s.set[val] = setVal
+}
+
+// Append adds multiple values into set.
+func (s *ThreadSafeTypedSet[T]) Append(values ...T) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	for _, val := range values {
+		s.add(val)
+	}
+}
+
+// Remove deletes the given value.
+func (s *ThreadSafeTypedSet[T]) Remove(val T) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	delete(s.set, val)
+}
+
+// Contains checks the value whether exists in the set.
+func (s *ThreadSafeTypedSet[T]) Contains(val T) bool {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.contains(val)
+}
+
+// contains is the implementation of the Contains method which is not
+// thread-safety. It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeTypedSet[T]) contains(val T) bool {
+	_, ok := s.set[val]
+	return ok
+}
+
+// Size returns the length of the set which means that number of value of the set.
+func (s *ThreadSafeTypedSet[T]) Size() uint {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return s.size()
+}
+
+// size is the implementation of the Size method which is not thread-safety.
+// It is called by other methods for avoiding deadlock.
+func (s *ThreadSafeTypedSet[T]) size() uint {
+	return uint(len(s.set))
+}
+
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns the zero value of T. Use TryPop for distinguishing
+// a zero value from the empty set.
+func (s *ThreadSafeTypedSet[T]) Pop() T {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns the zero value of T and false.
+func (s *ThreadSafeTypedSet[T]) TryPop() (T, bool) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	for val := range s.set {
+		delete(s.set, val)
+		return val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// PopN removes at most n random values from the set and returns them. The
+// values are removed atomically, so no other goroutine can observe the set in
+// the middle of the PopN. If the set has less than n elements, it removes and
+// returns all of them.
+func (s *ThreadSafeTypedSet[T]) PopN(n uint) []T {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	if n > s.size() {
+		n = s.size()
+	}
+	values := make([]T, 0, n)
+	for val := range s.set {
+		if uint(len(values)) == n {
+			break
+		}
+		delete(s.set, val)
+		values = append(values, val)
+	}
+	return values
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns the zero value of T.
+func (s *ThreadSafeTypedSet[T]) Peek() T {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for val := range s.set {
+		return val
+	}
+	var zero T
+	return zero
+}
+
+// Clear removes everything from the set.
+func (s *ThreadSafeTypedSet[T]) Clear() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.set = make(map[T]struct{})
+}
+
+// Empty checks whether the set is empty.
+func (s *ThreadSafeTypedSet[T]) Empty() bool {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	return len(s.set) == 0
+}
+
+// Slice returns the elements of the set as a slice. The elements can be in any
+// order.
+func (s *ThreadSafeTypedSet[T]) Slice() []T {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	values := make([]T, s.size())
+
+	i := 0
+	for k := range s.set {
+		values[i] = k
+		i++
+	}
+	return values
+}
+
+// Each calls fn for every value in the set until fn returns false. The values
+// are visited in any order. The read lock is held during the iteration, so fn
+// must not modify the set.
+func (s *ThreadSafeTypedSet[T]) Each(fn func(val T) bool) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for val := range s.set {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set which can be used with
+// for range. The values are visited in any order. The read lock is held while
+// the loop runs, so the loop body must not modify the set.
+//
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ThreadSafeTypedSet[T]) All() iter.Seq[T] {
+	return s.Each
+}
+
+// lockWith acquires the read locks which are needed for the binary operations
+// and returns the values of the given set with the function that releases the
+// locks. If the given set is a *ThreadSafeTypedSet, its map is used directly
+// under its read lock. The locks are acquired by rLockBoth, so the lock order is
+// the same for every pair of sets and s.Union(s) does not lock s recursively.
+// Otherwise, the values of the given set are copied before s is locked. The
+// returned map must not be modified.
+func (s *ThreadSafeTypedSet[T]) lockWith(set TypedSet[T]) (map[T]struct{}, func()) {
+	if o, ok := set.(*ThreadSafeTypedSet[T]); ok {
+		unlock := rLockBoth(&s.rw, &o.rw)
+		return o.set, unlock
+	}
+
+	values := set.Slice()
+	o := make(map[T]struct{}, len(values))
+	for _, val := range values {
+		o[val] = setVal
+	}
+	s.rw.RLock()
+	return o, s.rw.RUnlock
+}
+
+// Union returns a new TypedSet that contains all items from the receiver set
+// and all items from the given set. The given set can be any TypedSet
+// implementation and the returned set is always a *ThreadSafeTypedSet.
+func (s *ThreadSafeTypedSet[T]) Union(set TypedSet[T]) TypedSet[T] {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	unionSet := newThreadSafeTypedSet[T]()
+	for val := range s.set {
+		unionSet.add(val)
+	}
+	for val := range o {
+		unionSet.add(val)
+	}
+	return unionSet
+}
+
+// Intersection takes the common values from both sets and returns a new set
+// that stores the common ones. The given set can be any TypedSet implementation
+// and the returned set is always a *ThreadSafeTypedSet.
+func (s *ThreadSafeTypedSet[T]) Intersection(set TypedSet[T]) TypedSet[T] {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	intersectSet := newThreadSafeTypedSet[T]()
+	for val := range s.set {
+		if _, ok := o[val]; ok {
+			intersectSet.add(val)
+		}
+	}
+	return intersectSet
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new set. The given set can be any TypedSet implementation and the returned
+// set is always a *ThreadSafeTypedSet.
+func (s *ThreadSafeTypedSet[T]) Difference(set TypedSet[T]) TypedSet[T] {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	diffSet := newThreadSafeTypedSet[T]()
+	for val := range s.set {
+		if _, ok := o[val]; !ok {
+			diffSet.add(val)
+		}
+	}
+	return diffSet
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. The given set can be any TypedSet implementation.
+func (s *ThreadSafeTypedSet[T]) IsSubset(set TypedSet[T]) bool {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	if s.size() > uint(len(o)) {
+		return false
+	}
+
+	for val := range s.set {
+		if _, ok := o[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. The given set can be any TypedSet implementation.
+func (s *ThreadSafeTypedSet[T]) IsSuperset(set TypedSet[T]) bool {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	if s.size() < uint(len(o)) {
+		return false
+	}
+
+	for val := range o {
+		if !s.contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. The
+// given set can be any TypedSet implementation.
+func (s *ThreadSafeTypedSet[T]) IsDisjoint(set TypedSet[T]) bool {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	if s.size() == 0 || len(o) == 0 {
+		return true
+	}
+	for val := range s.set {
+		if _, ok := o[val]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks whether both sets contain exactly the same values. The given
+// set can be any TypedSet implementation.
+func (s *ThreadSafeTypedSet[T]) Equal(set TypedSet[T]) bool {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	if s.size() != uint(len(o)) {
+		return false
+	}
+
+	for val := range s.set {
+		if _, ok := o[val]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricDifference returns a set that contains from two sets, but not the
+// items are present in both sets. The given set can be any TypedSet
+// implementation and the returned set is always a *ThreadSafeTypedSet. Both
+// sets are read under the same locks, so the result is consistent even if the
+// sets are modified concurrently.
+func (s *ThreadSafeTypedSet[T]) SymmetricDifference(set TypedSet[T]) TypedSet[T] {
+	o, unlock := s.lockWith(set)
+	defer unlock()
+
+	symDiffSet := newThreadSafeTypedSet[T]()
+	for val := range s.set {
+		if _, ok := o[val]; !ok {
+			symDiffSet.add(val)
+		}
+	}
+	for val := range o {
+		if !s.contains(val) {
+			symDiffSet.add(val)
+		}
+	}
+	return symDiffSet
+}
@@ -0,0 +1,209 @@
+package set
+
+import (
+	"testing"
+)
+
+func TestThreadSafeTypedSet_Add(t *testing.T) {
+	testCases := []struct {
+		name    string
+		values  []int
+		expSize uint
+	}{
+		{
+			name:    "Add single value",
+			values:  []int{100},
+			expSize: 1,
+		},
+		{
+			name:    "Add duplicate values",
+			values:  []int{1, 1, 2},
+			expSize: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newThreadSafeTypedSet[int]()
+			for _, v := range tc.values {
+				s.Add(v)
+			}
+			if s.Size() != tc.expSize {
+				t.Errorf("expected length %v, actual length %v", tc.expSize, s.Size())
+			}
+			for _, v := range tc.values {
+				if !s.Contains(v) {
+					t.Errorf("value %v is expected, but not exist", v)
+				}
+			}
+		})
+	}
+}
+
+func TestThreadSafeTypedSet_Remove(t *testing.T) {
+	s := newThreadSafeTypedSet[string]()
+	s.Append("a", "b", "c")
+	s.Remove("b")
+	s.Remove("x")
+	if s.Size() != 2 {
+		t.Errorf("expected size 2, actual size %v", s.Size())
+	}
+	if s.Contains("b") {
+		t.Errorf("b is removed, but exists")
+	}
+}
+
+func TestThreadSafeTypedSet_Pop(t *testing.T) {
+	s := newThreadSafeTypedSet[int]()
+	if val := s.Pop(); val != 0 {
+		t.Errorf("expected zero value, actual %v", val)
+	}
+	if val, ok := s.TryPop(); ok {
+		t.Errorf("expected false, actual %v and %v", val, ok)
+	}
+	s.Append(0, 1, 2, 3)
+	if val := s.Peek(); !s.Contains(val) {
+		t.Errorf("%v not exist in set", val)
+	}
+	if val := s.Pop(); s.Contains(val) {
+		t.Errorf("%v is popped, but still exists", val)
+	}
+	if values := s.PopN(5); len(values) != 3 {
+		t.Errorf("expected length 3, actual length %v", len(values))
+	}
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
+
+func TestThreadSafeTypedSet_Clear(t *testing.T) {
+	s := newThreadSafeTypedSet[int]()
+	s.Append(1, 2, 3)
+	s.Clear()
+	if !s.Empty() {
+		t.Errorf("expected empty set, actual size %v", s.Size())
+	}
+}
+
+func TestThreadSafeTypedSet_Slice(t *testing.T) {
+	s := newThreadSafeTypedSet[int]()
+	s.Append(1, 2, 3)
+	values := s.Slice()
+	if len(values) != 3 {
+		t.Errorf("expected length 3, actual length %v", len(values))
+	}
+	for _, v := range values {
+		if !s.Contains(v) {
+			t.Errorf("%v not exist in set", v)
+		}
+	}
+}
+
+func TestThreadSafeTypedSet_SetOperations(t *testing.T) {
+	testCases := []struct {
+		name        string
+		values1     []int
+		values2     []int
+		expUnion    []int
+		expIntersec []int
+		expDiff     []int
+		expSymDiff  []int
+		expSubset   bool
+		expSuperset bool
+		expDisjoint bool
+		expEqual    bool
+	}{
+		{
+			name:        "Both empty sets",
+			expSubset:   true,
+			expSuperset: true,
+			expDisjoint: true,
+			expEqual:    true,
+		},
+		{
+			name:        "Overlapping sets",
+			values1:     []int{1, 2, 3},
+			values2:     []int{2, 3, 4},
+			expUnion:    []int{1, 2, 3, 4},
+			expIntersec: []int{2, 3},
+			expDiff:     []int{1},
+			expSymDiff:  []int{1, 4},
+		},
+		{
+			name:        "Subset",
+			values1:     []int{1, 2},
+			values2:     []int{1, 2, 3},
+			expUnion:    []int{1, 2, 3},
+			expIntersec: []int{1, 2},
+			expSymDiff:  []int{3},
+			expSubset:   true,
+		},
+		{
+			name:        "Disjoint sets",
+			values1:     []int{1, 2},
+			values2:     []int{3},
+			expUnion:    []int{1, 2, 3},
+			expDiff:     []int{1, 2},
+			expSymDiff:  []int{1, 2, 3},
+			expDisjoint: true,
+		},
+		{
+			name:        "Equal sets",
+			values1:     []int{1, 2},
+			values2:     []int{2, 1},
+			expUnion:    []int{1, 2},
+			expIntersec: []int{1, 2},
+			expSubset:   true,
+			expSuperset: true,
+			expEqual:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s1 := newThreadSafeTypedSet[int]()
+			s2 := newThreadSafeTypedSet[int]()
+			s1.Append(tc.values1...)
+			s2.Append(tc.values2...)
+
+			checkTypedSet(t, "union", s1.Union(s2), tc.expUnion)
+			checkTypedSet(t, "intersection", s1.Intersection(s2), tc.expIntersec)
+			checkTypedSet(t, "difference", s1.Difference(s2), tc.expDiff)
+			checkTypedSet(t, "symmetric difference", s1.SymmetricDifference(s2), tc.expSymDiff)
+			if s1.IsSubset(s2) != tc.expSubset {
+				t.Errorf("expected subset %v, actual %v", tc.expSubset, !tc.expSubset)
+			}
+			if s1.IsSuperset(s2) != tc.expSuperset {
+				t.Errorf("expected superset %v, actual %v", tc.expSuperset, !tc.expSuperset)
+			}
+			if s1.IsDisjoint(s2) != tc.expDisjoint {
+				t.Errorf("expected disjoint %v, actual %v", tc.expDisjoint, !tc.expDisjoint)
+			}
+			if s1.Equal(s2) != tc.expEqual {
+				t.Errorf("expected equal %v, actual %v", tc.expEqual, !tc.expEqual)
+			}
+		})
+	}
+}
+
+func TestThreadSafeTypedSet_All(t *testing.T) {
+	s := newThreadSafeTypedSet[int]()
+	s.Append(1, 2, 3, 4)
+
+	sum := 0
+	for val := range s.All() {
+		sum += val
+	}
+	if sum != 10 {
+		t.Errorf("expected sum 10, actual sum %v", sum)
+	}
+
+	calls := 0
+	s.Each(func(val int) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("expected 1 call, actual %v calls", calls)
+	}
+}
@@ -0,0 +1,341 @@
+package set
+
+import "iter"
+
+// ThreadUnsafeTypedSet is the generic version of ThreadUnsafeSet. It does not
+// provide the thread-safety.
+type ThreadUnsafeTypedSet[T comparable] struct {
+	set map[T]struct{}
+}
+
+// newThreadUnsafeTypedSet creates a new *ThreadUnsafeTypedSet.
+func newThreadUnsafeTypedSet[T comparable]() *ThreadUnsafeTypedSet[T] {
+	return &ThreadUnsafeTypedSet[T]{set: make(map[T]struct{})}
+}
+
+// Add adds a new value to set. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Add("str")
+func (s *ThreadUnsafeTypedSet[T]) Add(val T) {
+	s.set[val] = setVal
+}
+
+// Append adds multiple values into set. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	s.Append(1, 2, 3, 4)
+func (s *ThreadUnsafeTypedSet[T]) Append(values ...T) {
+	for _, val := range values {
+		s.Add(val)
+	}
+}
+
+// Remove deletes the given value. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	s.Remove(2)
+func (s *ThreadUnsafeTypedSet[T]) Remove(val T) {
+	delete(s.set, val)
+}
+
+// Contains checks the value whether exists in the set. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	exist := s.Contains(1)
+func (s *ThreadUnsafeTypedSet[T]) Contains(val T) bool {
+	_, ok := s.set[val]
+	return ok
+}
+
+// Size returns the length of the set which means that number of value of the set.
+// It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	size := s.Size()
+func (s *ThreadUnsafeTypedSet[T]) Size() uint {
+	return uint(len(s.set))
+}
+
+// Pop removes a random value from the set and returns it. If there is no
+// element in set, it returns the zero value of T. Use TryPop for distinguishing
+// a zero value from the empty set. It is not a thread-safe method. It does not
+// handle the concurrency.
+//
+// Example:
+//	val := s.Pop()
+func (s *ThreadUnsafeTypedSet[T]) Pop() T {
+	val, _ := s.TryPop()
+	return val
+}
+
+// TryPop removes a random value from the set and returns it with true. If there
+// is no element in set, it returns the zero value of T and false. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	val, ok := s.TryPop()
+func (s *ThreadUnsafeTypedSet[T]) TryPop() (T, bool) {
+	for val := range s.set {
+		delete(s.set, val)
+		return val, true
+	}
+	var zero T
+	return zero, false
+}
+
+// PopN removes at most n random values from the set and returns them. If the
+// set has less than n elements, it removes and returns all of them. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	values := s.PopN(10)
+func (s *ThreadUnsafeTypedSet[T]) PopN(n uint) []T {
+	if n > s.Size() {
+		n = s.Size()
+	}
+	values := make([]T, 0, n)
+	for val := range s.set {
+		if uint(len(values)) == n {
+			break
+		}
+		delete(s.set, val)
+		values = append(values, val)
+	}
+	return values
+}
+
+// Peek returns a random value from the set without removing it. If there is no
+// element in set, it returns the zero value of T. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	val := s.Peek()
+func (s *ThreadUnsafeTypedSet[T]) Peek() T {
+	for val := range s.set {
+		return val
+	}
+	var zero T
+	return zero
+}
+
+// Clear removes everything from the set. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	s.Clear()
+func (s *ThreadUnsafeTypedSet[T]) Clear() {
+	s.set = make(map[T]struct{})
+}
+
+// Empty checks whether the set is empty. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	empty := s.Empty()
+func (s *ThreadUnsafeTypedSet[T]) Empty() bool {
+	return len(s.set) == 0
+}
+
+// Slice returns the elements of the set as a slice. The elements can be in any
+// order. It is not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	setSlice := s.Slice()
+func (s *ThreadUnsafeTypedSet[T]) Slice() []T {
+	values := make([]T, s.Size())
+
+	i := 0
+	for k := range s.set {
+		values[i] = k
+		i++
+	}
+	return values
+}
+
+// Each calls fn for every value in the set until fn returns false. The values
+// are visited in any order. Removing values from fn is safe, but the values
+// added by fn may or may not be visited. It is not a thread-safe method. It
+// does not handle the concurrency.
+//
+// Example:
+//	s.Each(func(val T) bool {
+//		fmt.Println(val)
+//		return true
+//	})
+func (s *ThreadUnsafeTypedSet[T]) Each(fn func(val T) bool) {
+	for val := range s.set {
+		if !fn(val) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the values of the set which can be used with
+// for range. It follows the same rules with Each. It is not a thread-safe
+// method. It does not handle the concurrency.
+//
+// Example:
+//	for val := range s.All() {
+//		fmt.Println(val)
+//	}
+func (s *ThreadUnsafeTypedSet[T]) All() iter.Seq[T] {
+	return s.Each
+}
+
+// Union returns a new TypedSet that contains all items from the receiver set
+// and all items from the given set. The given set can be any TypedSet
+// implementation and the returned set is always a *ThreadUnsafeTypedSet. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	unionSet := s1.Union(s2)
+func (s *ThreadUnsafeTypedSet[T]) Union(set TypedSet[T]) TypedSet[T] {
+	unionSet := newThreadUnsafeTypedSet[T]()
+	for val := range s.set {
+		unionSet.Add(val)
+	}
+	if o, ok := set.(*ThreadUnsafeTypedSet[T]); ok {
+		for val := range o.set {
+			unionSet.Add(val)
+		}
+	} else {
+		unionSet.Append(set.Slice()...)
+	}
+	return unionSet
+}
+
+// Intersection takes the common values from both sets and returns a new set
+// that stores the common ones. The given set can be any TypedSet implementation
+// and the returned set is always a *ThreadUnsafeTypedSet. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	intersectionSet := s1.Intersection(s2)
+func (s *ThreadUnsafeTypedSet[T]) Intersection(set TypedSet[T]) TypedSet[T] {
+	intersectSet := newThreadUnsafeTypedSet[T]()
+	for val := range s.set {
+		if set.Contains(val) {
+			intersectSet.Add(val)
+		}
+	}
+	return intersectSet
+}
+
+// Difference takes the items that only is stored in s, receiver set. It returns
+// a new set. The given set can be any TypedSet implementation and the returned
+// set is always a *ThreadUnsafeTypedSet. It is not a thread-safe method. It does
+// not handle the concurrency.
+//
+// Example:
+//	diffSet := s1.Difference(s2)
+func (s *ThreadUnsafeTypedSet[T]) Difference(set TypedSet[T]) TypedSet[T] {
+	diffSet := newThreadUnsafeTypedSet[T]()
+	for val := range s.set {
+		if !set.Contains(val) {
+			diffSet.Add(val)
+		}
+	}
+	return diffSet
+}
+
+// IsSubset returns true if all items in the set exist in the given set.
+// Otherwise, it returns false. It is not a thread-safe method. It does not handle
+// the concurrency.
+//
+// Example:
+//	isSubset := s1.IsSubset(s2)
+func (s *ThreadUnsafeTypedSet[T]) IsSubset(set TypedSet[T]) bool {
+	if s.Size() > set.Size() {
+		return false
+	}
+
+	for val := range s.set {
+		if !set.Contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all items in the given set exist in the set.
+// Otherwise, it returns false. The given set can be any TypedSet
+// implementation. It is not a thread-safe method. It does not handle the
+// concurrency.
+//
+// Example:
+//	isSuperset := s1.IsSuperset(s2)
+func (s *ThreadUnsafeTypedSet[T]) IsSuperset(set TypedSet[T]) bool {
+	if o, ok := set.(*ThreadUnsafeTypedSet[T]); ok {
+		if s.Size() < o.Size() {
+			return false
+		}
+		for val := range o.set {
+			if !s.Contains(val) {
+				return false
+			}
+		}
+		return true
+	}
+
+	values := set.Slice()
+	if s.Size() < uint(len(values)) {
+		return false
+	}
+	for _, val := range values {
+		if !s.Contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDisjoint returns true if none of the items are present in the sets. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	isDisjoint := s1.IsDisjoint(s2)
+func (s *ThreadUnsafeTypedSet[T]) IsDisjoint(set TypedSet[T]) bool {
+	if s.Size() == 0 || set.Size() == 0 {
+		return true
+	}
+	for val := range s.set {
+		if set.Contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks whether both sets contain exactly the same values. It is not a
+// thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	equal := s1.Equal(s2)
+func (s *ThreadUnsafeTypedSet[T]) Equal(set TypedSet[T]) bool {
+	if s.Size() != set.Size() {
+		return false
+	}
+
+	for val := range s.set {
+		if !set.Contains(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricDifference returns a set that contains from two sets, but not the
+// items are present in both sets. The given set can be any TypedSet
+// implementation and the returned set is always a *ThreadUnsafeTypedSet. It is
+// not a thread-safe method. It does not handle the concurrency.
+//
+// Example:
+//	symmetricDiffSet := s1.SymmetricDifference(s2)
+func (s *ThreadUnsafeTypedSet[T]) SymmetricDifference(set TypedSet[T]) TypedSet[T] {
+	return s.Difference(set).Union(set.Difference(s))
+}
@@ -0,0 +1,245 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// defaultWatchBuffer is the number of the events which are buffered for a
+// watcher if WithWatchBuffer is not given.
+const defaultWatchBuffer = 64
+
+// ChangeKind is the kind of a ChangeEvent.
+type ChangeKind uint8
+
+const (
+	// ChangeAdded means that Value is added into the set.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved means that Value is removed from the set.
+	ChangeRemoved
+	// ChangeResync means that the events up to Seq are dropped because the
+	// watcher could not keep up with the set, so the watcher should read the
+	// whole set again.
+	ChangeResync
+)
+
+// String returns the name of the kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeResync:
+		return "resync"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", k)
+}
+
+// ChangeEvent is a change of a ThreadSafeSet which is sent by Watch.
+type ChangeEvent struct {
+	Kind ChangeKind
+	// Value is the added or removed value. It is nil for ChangeResync.
+	Value interface{}
+	// Seq is the sequence number of the change. The changes of a set are
+	// numbered in the order they are done, starting with 1, and every watcher
+	// receives them in that order. A gap between the sequence numbers means
+	// that the events are dropped.
+	Seq uint64
+}
+
+// SlowConsumerPolicy defines what happens when the buffer of a watcher is full.
+type SlowConsumerPolicy uint8
+
+const (
+	// WatchResync drops the buffered events and replaces them with a single
+	// ChangeResync event. The writers never wait for the watcher. It is the
+	// default policy.
+	WatchResync SlowConsumerPolicy = iota
+	// WatchDrop drops the new events until the watcher reads from the channel.
+	// The writers never wait for the watcher.
+	WatchDrop
+	// WatchBlock makes the writers wait until the watcher reads their events.
+	// The writers wait after releasing the lock of the set, so the watcher can
+	// still use the set, but it must not wait for a writer of the same set.
+	WatchBlock
+)
+
+// WatchOption configures a watcher which is created by Watch.
+type WatchOption func(w *watcher)
+
+// WithWatchBuffer sets the number of the events which can wait for the
+// watcher. It panics if n is zero.
+func WithWatchBuffer(n uint) WatchOption {
+	if n == 0 {
+		panic("set: watch buffer must be positive")
+	}
+	return func(w *watcher) {
+		w.buffer = int(n)
+	}
+}
+
+// WithSlowConsumerPolicy sets the policy which is applied when the buffer of
+// the watcher is full.
+func WithSlowConsumerPolicy(policy SlowConsumerPolicy) WatchOption {
+	return func(w *watcher) {
+		w.policy = policy
+	}
+}
+
+// Watch returns a channel which receives the changes of the set until ctx is
+// cancelled. Only the actual changes are sent, so adding an existing value or
+// removing a missing value does not send an event, and Clear sends a
+// ChangeRemoved event for every value. The changes which are done before Watch
+// returns are not sent.
+//
+// The events wait in a buffer of 64 events by default until they are read, and
+// the SlowConsumerPolicy decides what happens when the buffer is full. The
+// channel is closed when ctx is cancelled, and the buffered events are
+// discarded.
+//
+//	events := s.Watch(ctx, set.WithWatchBuffer(1024))
+//	for ev := range events {
+//		if ev.Kind == set.ChangeResync {
+//			reload(s.Slice())
+//		}
+//	}
+func (s *ThreadSafeSet) Watch(ctx context.Context, opts ...WatchOption) <-chan ChangeEvent {
+	w := &watcher{buffer: defaultWatchBuffer}
+	w.cond.L = &w.mu
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	s.rw.Lock()
+	s.watchers = append(slices.Clip(s.watchers), w)
+	s.rw.Unlock()
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		defer s.unwatch(w)
+		stop := context.AfterFunc(ctx, w.close)
+		defer stop()
+		for {
+			ev, ok := w.next()
+			if !ok {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// unwatch removes w from the watchers of the set.
+func (s *ThreadSafeSet) unwatch(w *watcher) {
+	w.close()
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	s.watchers = slices.DeleteFunc(slices.Clone(s.watchers), func(o *watcher) bool {
+		return o == w
+	})
+	if len(s.watchers) == 0 {
+		s.watchers = nil
+	}
+}
+
+// emit sends a change to the watchers. It must be called under the write lock.
+func (s *ThreadSafeSet) emit(kind ChangeKind, val interface{}) {
+	s.seq++
+	ev := ChangeEvent{Kind: kind, Value: val, Seq: s.seq}
+	for _, w := range s.watchers {
+		w.push(ev)
+	}
+}
+
+// unlock releases the write lock, and then waits for the watchers which have
+// the WatchBlock policy.
+func (s *ThreadSafeSet) unlock() {
+	watchers := s.watchers
+	s.rw.Unlock()
+	for _, w := range watchers {
+		if w.policy == WatchBlock {
+			w.wait()
+		}
+	}
+}
+
+// watcher buffers the events of a Watch call until they are sent to its
+// channel. The events are pushed under the lock of the set, so they are
+// buffered in the order of their sequence numbers.
+type watcher struct {
+	mu     sync.Mutex
+	cond   sync.Cond
+	queue  []ChangeEvent
+	buffer int
+	policy SlowConsumerPolicy
+	closed bool
+}
+
+// push adds ev into the buffer by applying the policy of the watcher. It never
+// waits, the writers wait in the wait method for the WatchBlock policy.
+func (w *watcher) push(ev ChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	if len(w.queue) >= w.buffer {
+		switch w.policy {
+		case WatchDrop:
+			return
+		case WatchResync:
+			clear(w.queue)
+			w.queue = append(w.queue[:0], ChangeEvent{Kind: ChangeResync, Seq: ev.Seq})
+			return
+		}
+	}
+	w.queue = append(w.queue, ev)
+	w.cond.Broadcast()
+}
+
+// wait waits until the buffered events fit into the buffer. A single change
+// such as Clear may push more events than the buffer, so the writer waits
+// until the watcher reads the excess.
+func (w *watcher) wait() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.queue) > w.buffer && !w.closed {
+		w.cond.Wait()
+	}
+}
+
+// next removes the first event from the buffer and returns it with true. It
+// waits if the buffer is empty, and returns false when the watcher is closed.
+func (w *watcher) next() (ChangeEvent, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.queue) == 0 && !w.closed {
+		w.cond.Wait()
+	}
+	if w.closed {
+		return ChangeEvent{}, false
+	}
+	ev := w.queue[0]
+	w.queue[0] = ChangeEvent{}
+	w.queue = w.queue[1:]
+	w.cond.Broadcast()
+	return ev, true
+}
+
+// close stops the watcher and releases the waiting writers.
+func (w *watcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	w.queue = nil
+	w.cond.Broadcast()
+}
@@ -0,0 +1,201 @@
+package set
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// receive reads n events from events and fails if the channel is closed.
+func receive(t *testing.T, events <-chan ChangeEvent, n int) []ChangeEvent {
+	t.Helper()
+	received := make([]ChangeEvent, 0, n)
+	for len(received) < n {
+		ev, ok := <-events
+		if !ok {
+			t.Fatalf("channel is closed after %v events", len(received))
+		}
+		received = append(received, ev)
+	}
+	return received
+}
+
+func TestThreadSafeSet_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newThreadSafeSet()
+	s.Add(0)
+	events := s.Watch(ctx)
+
+	s.Add(1)
+	s.Add(1)
+	s.Append(2, 3, 2)
+	s.Remove(4)
+	s.Remove(2)
+	exp := []ChangeEvent{
+		{Kind: ChangeAdded, Value: 1, Seq: 1},
+		{Kind: ChangeAdded, Value: 2, Seq: 2},
+		{Kind: ChangeAdded, Value: 3, Seq: 3},
+		{Kind: ChangeRemoved, Value: 2, Seq: 4},
+	}
+	if received := receive(t, events, len(exp)); !reflect.DeepEqual(received, exp) {
+		t.Errorf("expected %v, actual %v", exp, received)
+	}
+
+	popped := s.Pop()
+	s.Clear()
+	s.Clear()
+	removed := newThreadUnsafeSet()
+	for i, ev := range receive(t, events, 3) {
+		if ev.Kind != ChangeRemoved || ev.Seq != uint64(5+i) {
+			t.Errorf("expected removed event %v, actual %v", 5+i, ev)
+		}
+		removed.Add(ev.Value)
+	}
+	checkSameSet(t, "removed", removed, NewOf(ThreadUnsafe, 0, 1, 3))
+	if !removed.Contains(popped) {
+		t.Errorf("expected popped value %v is removed", popped)
+	}
+
+	cancel()
+	for ev := range events {
+		t.Errorf("unexpected event %v after cancel", ev)
+	}
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	if s.watchers != nil {
+		t.Errorf("expected no watchers after cancel, actual %v", len(s.watchers))
+	}
+}
+
+func TestThreadSafeSet_WatchMultiple(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newThreadSafeSet()
+	first := s.Watch(ctx)
+	s.Add("a")
+	second := s.Watch(ctx)
+	s.Add("b")
+
+	expFirst := []ChangeEvent{{Kind: ChangeAdded, Value: "a", Seq: 1}, {Kind: ChangeAdded, Value: "b", Seq: 2}}
+	if received := receive(t, first, 2); !reflect.DeepEqual(received, expFirst) {
+		t.Errorf("expected %v, actual %v", expFirst, received)
+	}
+	expSecond := []ChangeEvent{{Kind: ChangeAdded, Value: "b", Seq: 2}}
+	if received := receive(t, second, 1); !reflect.DeepEqual(received, expSecond) {
+		t.Errorf("expected %v, actual %v", expSecond, received)
+	}
+}
+
+func TestThreadSafeSet_WatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := newThreadSafeSet()
+	events := s.Watch(ctx, WithSlowConsumerPolicy(WatchBlock), WithWatchBuffer(1))
+	// The writers must not wait for a cancelled watcher.
+	s.Append(1, 2, 3)
+	for range events {
+	}
+	s.Append(4, 5, 6)
+}
+
+func TestThreadSafeSet_WatchBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newThreadSafeSet()
+	events := s.Watch(ctx, WithSlowConsumerPolicy(WatchBlock), WithWatchBuffer(1))
+
+	const writers, n = 4, 100
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				s.Add(w*n + i)
+			}
+		}(w)
+	}
+
+	added := newThreadUnsafeSet()
+	for i, ev := range receive(t, events, writers*n) {
+		if ev.Kind != ChangeAdded || ev.Seq != uint64(i+1) {
+			t.Fatalf("expected added event %v, actual %v", i+1, ev)
+		}
+		// The watcher can use the set while the writers are waiting.
+		if !s.Contains(ev.Value) {
+			t.Errorf("expected %v in set", ev.Value)
+		}
+		added.Add(ev.Value)
+	}
+	wg.Wait()
+	checkSameSet(t, "added", added, s)
+}
+
+func TestWatcher_SlowConsumerPolicy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy SlowConsumerPolicy
+		exp    []ChangeEvent
+	}{
+		{
+			name:   "Drop",
+			policy: WatchDrop,
+			exp:    []ChangeEvent{{Kind: ChangeAdded, Value: 1, Seq: 1}, {Kind: ChangeAdded, Value: 2, Seq: 2}},
+		},
+		{
+			name:   "Resync",
+			policy: WatchResync,
+			exp:    []ChangeEvent{{Kind: ChangeResync, Seq: 5}},
+		},
+		{
+			name:   "Block",
+			policy: WatchBlock,
+			exp: []ChangeEvent{
+				{Kind: ChangeAdded, Value: 1, Seq: 1},
+				{Kind: ChangeAdded, Value: 2, Seq: 2},
+				{Kind: ChangeAdded, Value: 3, Seq: 3},
+				{Kind: ChangeAdded, Value: 4, Seq: 4},
+				{Kind: ChangeAdded, Value: 5, Seq: 5},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &watcher{buffer: 2, policy: tc.policy}
+			w.cond.L = &w.mu
+			for i := 1; i <= 5; i++ {
+				w.push(ChangeEvent{Kind: ChangeAdded, Value: i, Seq: uint64(i)})
+			}
+
+			var received []ChangeEvent
+			for len(w.queue) > 0 {
+				ev, _ := w.next()
+				received = append(received, ev)
+			}
+			if !reflect.DeepEqual(received, tc.exp) {
+				t.Errorf("expected %v, actual %v", tc.exp, received)
+			}
+
+			w.push(ChangeEvent{Kind: ChangeRemoved, Value: 1, Seq: 6})
+			if ev, _ := w.next(); ev.Seq != 6 {
+				t.Errorf("expected event 6 after draining, actual %v", ev)
+			}
+			w.close()
+			if _, ok := w.next(); ok {
+				t.Errorf("expected no event after close")
+			}
+		})
+	}
+}
+
+func TestWithWatchBuffer_Zero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for zero buffer")
+		}
+	}()
+	WithWatchBuffer(0)
+}